@@ -0,0 +1,106 @@
+// Command golens-ts generates TypeScript filter types and query builders
+// from capabilities documents (see filter.CapabilitiesHTTPHandler).
+//
+// Usage:
+//
+//	golens-ts [-o filters.ts] name=source...
+//
+// Each source is a JSON file or the http(s) URL of a capabilities endpoint:
+//
+//	golens-ts -o src/api/filters.ts \
+//		users=http://localhost:8080/users/capabilities \
+//		orders=capabilities/orders.json
+//
+// Capabilities hide fields the caller lacks permissions for, so fetch them
+// as a user who holds every permission the client needs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vidinfra/golens/filter"
+)
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: golens-ts [-o file] name=source...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Args(), *out); err != nil {
+		fmt.Fprintln(os.Stderr, "golens-ts:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out string) error {
+	schemas := make(map[string]*filter.Capabilities, len(args))
+	for _, arg := range args {
+		name, source, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || source == "" {
+			return fmt.Errorf("argument %q is not name=source", arg)
+		}
+		if _, dup := schemas[name]; dup {
+			return fmt.Errorf("schema %q given twice", name)
+		}
+		caps, err := load(source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		schemas[name] = caps
+	}
+
+	var buf bytes.Buffer
+	if err := filter.GenerateTypeScript(&buf, schemas); err != nil {
+		return err
+	}
+	if out == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(out, buf.Bytes(), 0o644)
+}
+
+// load reads a capabilities document from a file or URL.
+func load(source string) (*filter.Capabilities, error) {
+	var r io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var caps filter.Capabilities
+	if err := json.NewDecoder(r).Decode(&caps); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", source, err)
+	}
+	return &caps, nil
+}
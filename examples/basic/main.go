@@ -6,7 +6,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/vidinfra/golens/filter"
+	"github.com/vidinfra/golens/filter/ginfilter"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -47,7 +47,7 @@ func main() {
 	r.GET("/users", func(c *gin.Context) {
 		base := db.Model(&User{})
 
-		builder := filter.New(c, base).
+		builder := ginfilter.New(c, base).
 			AllowFields("name", "email", "age", "status").
 			AllowSorts("name", "age", "status").AllowSorts("name", "age")
 		builder.Apply()
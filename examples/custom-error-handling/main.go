@@ -14,6 +14,7 @@ import (
 	// "gorm.io/driver/sqlite"
 
 	"github.com/vidinfra/golens/filter"
+	"github.com/vidinfra/golens/filter/ginfilter"
 )
 
 type User struct {
@@ -41,13 +42,13 @@ func main() {
 		query := db.Model(&User{})
 
 		// Create filter with struct-first error handling
-		result := filter.New(c, query).
+		result := ginfilter.New(c, query).
 			AllowFields("name", "email", "age", "status").
 			AllowSorts("name", "age", "created_at").
 			Apply()
 
 		// Advanced struct-first error handling with custom logic
-		if result.OK() {
+		if !result.OK() {
 			errors := result.GetErrors()
 
 			// Example 1: Custom error categorization
@@ -123,11 +124,11 @@ func main() {
 	r.GET("/users/i18n", func(c *gin.Context) {
 		query := db.Model(&User{})
 
-		result := filter.New(c, query).
+		result := ginfilter.New(c, query).
 			AllowFields("name", "email").
 			Apply()
 
-		if result.OK() {
+		if !result.OK() {
 			translatedErrors := translateErrors(result.GetErrors(), "en")
 			c.JSON(http.StatusBadRequest, translatedErrors)
 			return
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -9,6 +10,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/vidinfra/golens/filter"
+	"github.com/vidinfra/golens/filter/ginfilter"
 )
 
 type Product struct {
@@ -48,47 +50,45 @@ func main() {
 	}
 	// -------------------------------------------
 
-	// Example endpoint with comprehensive filtering
-	r.GET("/products", func(c *gin.Context) {
-		// Start with base GORM query
-		query := db.Model(&Product{})
-
-		// Define filter configurations (same semantics as your Bun example)
-		configs := []filter.FilterConfig{
-			filter.AllowedFilter("name", filter.Equals, filter.Contains, filter.StartsWith, filter.EndsWith),
-			filter.AllowedFilter("description", filter.Contains),
-			filter.AllowedFilter("price", filter.Equals, filter.GreaterThan, filter.LessThan, filter.GreaterThanOrEq, filter.LessThanOrEq, filter.Between),
-			filter.AllowedFilter("category", filter.Equals, filter.In, filter.NotIn),
-			filter.AllowedFilter("status", filter.Equals, filter.In),
-			filter.AllowedFilter("created_at", filter.GreaterThan, filter.LessThan, filter.Between),
-		}
-
-		// Apply filters and sorting with struct-first error handling
-		result := filter.New(c, query).
-			AllowConfigs(configs...).
-			AllowSorts("name", "price", "created_at", "category").
-			Apply()
-
-		// Struct-first error handling with direct access to error details
-		if result.OK() {
-			errors := result.GetErrors()
-
-			// Log all errors for debugging
-			for _, err := range errors.Errors {
-				log.Printf("Filter error: %s (Field: %s, Code: %s, Type: %s)",
-					err.Message, err.Field, err.Code, err.Type)
-			}
+	// Filters are declared once per endpoint. ginfilter.Middleware applies them
+	// and answers invalid requests itself (400, or 403 for permission errors),
+	// so the handlers below only deal with the happy path.
+	products := func(b *filter.Builder) *filter.Builder {
+		return b.
+			AllowConfigs(
+				filter.AllowedFilter("name", filter.Equals, filter.Contains, filter.StartsWith, filter.EndsWith),
+				filter.AllowedFilter("description", filter.Contains),
+				filter.AllowedFilter("price", filter.Equals, filter.GreaterThan, filter.LessThan, filter.GreaterThanOrEq, filter.LessThanOrEq, filter.Between),
+				filter.AllowedFilter("category", filter.Equals, filter.In, filter.NotIn),
+				filter.AllowedFilter("status", filter.Equals, filter.In),
+				filter.AllowedFilter("created_at", filter.GreaterThan, filter.LessThan, filter.Between),
+			).
+			AllowSorts("name", "price", "created_at", "category")
+	}
 
-			// Quick response via helper
-			c.JSON(http.StatusBadRequest, result.Result().ToJSONResponse())
-			return
-		}
+	// Email is sensitive: only callers with "users:read-email" may filter on it.
+	users := func(b *filter.Builder) *filter.Builder {
+		return b.
+			AllowConfigs(
+				filter.AllowedFilter("name", filter.Equals, filter.Contains),
+				filter.AllowedFilter("email", filter.Equals).RequirePermissions("users:read-email"),
+				filter.AllowedFilter("status", filter.Equals, filter.In),
+				filter.AllowedFilter("created_at", filter.GreaterThan, filter.LessThan),
+			).
+			AllowSorts("name", "created_at").
+			WithAuthorizer(func(ctx context.Context, permission string) bool {
+				// Replace with a lookup of the authenticated user's roles.
+				return ctx.Value(roleKey{}) == "admin"
+			}).
+			WithErrorFormat(filter.FormatProblem)
+	}
 
-		finalQuery := result.Query() // *gorm.DB
+	r.Use(withRole)
 
-		// Execute the query
+	// Example endpoint with comprehensive filtering
+	r.GET("/products", ginfilter.Middleware(db.Model(&Product{}), products), func(c *gin.Context) {
 		var products []Product
-		if err := finalQuery.Find(&products).Error; err != nil {
+		if err := filter.QueryFromContext(c.Request.Context()).Find(&products).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
 				"errors": []map[string]interface{}{
@@ -109,41 +109,10 @@ func main() {
 		})
 	})
 
-	// Example with simple field allowlist and struct-first error handling
-	r.GET("/users", func(c *gin.Context) {
-		query := db.Model(&User{})
-
-		// If your library has AllowAll, use it; otherwise do AllowFields+AllowSorts explicitly:
-		result := filter.New(c, query).
-			AllowFields("name", "email", "status", "created_at").
-			AllowSorts("name", "created_at").
-			Apply()
-
-		// Direct struct access for custom error handling
-		if result.OK() {
-			errors := result.GetErrors()
-
-			// Custom logic based on error types
-			for _, err := range errors.Errors {
-				if err.Field == "email" {
-					// Special handling for sensitive email field
-					c.JSON(http.StatusForbidden, gin.H{
-						"error": "Email filtering requires special permissions",
-						"code":  "PERMISSION_DENIED",
-					})
-					return
-				}
-			}
-
-			// Use library's JSON helper for other errors
-			c.JSON(http.StatusBadRequest, result.Result().ToJSONResponse())
-			return
-		}
-
-		finalQuery := result.Query() // *gorm.DB
-
+	// Example with permission-checked filters and problem+json errors
+	r.GET("/users", ginfilter.Middleware(db.Model(&User{}), users), func(c *gin.Context) {
 		var users []User
-		if err := finalQuery.Find(&users).Error; err != nil {
+		if err := filter.QueryFromContext(c.Request.Context()).Find(&users).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"success": false,
 				"errors": []map[string]interface{}{
@@ -165,3 +134,12 @@ func main() {
 
 	r.Run(":8080")
 }
+
+type roleKey struct{}
+
+// withRole stands in for real authentication: it trusts the X-Role header.
+func withRole(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), roleKey{}, c.GetHeader("X-Role"))
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
@@ -0,0 +1,297 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// AggregateFunc is an aggregate function accepted in ?aggregate=.
+type AggregateFunc string
+
+const (
+	AggCount AggregateFunc = "count"
+	AggSum   AggregateFunc = "sum"
+	AggAvg   AggregateFunc = "avg"
+	AggMin   AggregateFunc = "min"
+	AggMax   AggregateFunc = "max"
+)
+
+// IsValid reports whether f is a supported aggregate function.
+func (f AggregateFunc) IsValid() bool {
+	switch f {
+	case AggCount, AggSum, AggAvg, AggMin, AggMax:
+		return true
+	default:
+		return false
+	}
+}
+
+// AggregateRow is one group of an aggregation. Group holds the group_by
+// columns and Values the requested aggregates, keyed as requested:
+//
+//	GET /orders?group_by=status&aggregate=count,sum(price)&having[count][gt]=5
+//
+//	[
+//	  {"group": {"status": "paid"},    "values": {"count": 12, "sum(price)": 340.5}},
+//	  {"group": {"status": "pending"}, "values": {"count": 7,  "sum(price)": 99}}
+//	]
+//
+// Rows are ordered by the group_by columns. Without group_by there is a
+// single row with an empty Group.
+type AggregateRow struct {
+	Group  map[string]any `json:"group"`
+	Values map[string]any `json:"values"`
+}
+
+// aggregateSpec is a parsed ?aggregate= item such as "sum(price)".
+type aggregateSpec struct {
+	key   string // as returned in AggregateRow.Values, e.g. "sum(price)"
+	fn    AggregateFunc
+	field string // empty for count(*)
+}
+
+// expr returns the SQL expression, e.g. SUM(price).
+func (s aggregateSpec) expr() string {
+	if s.field == "" {
+		return "COUNT(*)"
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(string(s.fn)), s.field)
+}
+
+// parseAggregateSpec parses "count", "count(*)" or "fn(field)".
+func parseAggregateSpec(item string) (aggregateSpec, bool) {
+	item = strings.ReplaceAll(item, " ", "")
+	if lower := strings.ToLower(item); lower == "count" || lower == "count(*)" {
+		return aggregateSpec{key: "count", fn: AggCount}, true
+	}
+	open := strings.IndexByte(item, '(')
+	if open <= 0 || !strings.HasSuffix(item, ")") {
+		return aggregateSpec{}, false
+	}
+	fn := AggregateFunc(strings.ToLower(item[:open]))
+	field := item[open+1 : len(item)-1]
+	if !fn.IsValid() || field == "" || field == "*" {
+		return aggregateSpec{}, false
+	}
+	return aggregateSpec{key: fmt.Sprintf("%s(%s)", fn, field), fn: fn, field: field}, true
+}
+
+// AllowGroupBy sets the allowlist of columns for ?group_by=.
+func (b *Builder) AllowGroupBy(fields ...string) *Builder {
+	b.allowedGroupBy = fields
+	return b
+}
+
+// AllowAggregate allows the given functions over field in ?aggregate=,
+// e.g. AllowAggregate("price", AggSum, AggAvg). A bare count is always allowed.
+func (b *Builder) AllowAggregate(field string, funcs ...AggregateFunc) *Builder {
+	if b.aggregates == nil {
+		b.aggregates = make(map[string][]AggregateFunc)
+	}
+	b.aggregates[field] = append(b.aggregates[field], funcs...)
+	return b
+}
+
+// Aggregate runs ?group_by=, ?aggregate= (default "count") and
+// having[aggregate][op]=value over the query filtered by Apply. Sorting,
+// includes, fieldsets and paging do not apply. See AggregateRow for the
+// shape of the result.
+func (b *Builder) Aggregate() ([]AggregateRow, *FilterErrors) {
+	if b.result == nil {
+		b.Apply()
+	}
+	if !b.OK() {
+		return nil, b.GetErrors()
+	}
+
+	errs := &FilterErrors{}
+	groupBy := b.parseGroupBy(errs)
+	specs := b.parseAggregates(errs)
+	having := b.parseHaving(specs, errs)
+	b.finishErrors(errs.Errors)
+	if !errs.OK() {
+		return nil, errs
+	}
+
+	selects := make([]string, 0, len(groupBy)+len(specs))
+	selects = append(selects, groupBy...)
+	for i, s := range specs {
+		selects = append(selects, fmt.Sprintf("%s AS agg_%d", s.expr(), i))
+	}
+
+	q := b.filtered.Session(&gorm.Session{}).Select(strings.Join(selects, ", "))
+	if len(groupBy) > 0 {
+		q = q.Group(strings.Join(groupBy, ", ")).Order(strings.Join(groupBy, ", "))
+	}
+	for _, h := range having {
+		q = q.Having(h.sql, h.args...)
+	}
+
+	var raw []map[string]any
+	if err := q.Find(&raw).Error; err != nil {
+		errs.Add(NewDatabaseError("Aggregation query failed", err))
+		return nil, errs
+	}
+
+	rows := make([]AggregateRow, 0, len(raw))
+	for _, r := range raw {
+		row := AggregateRow{Group: make(map[string]any, len(groupBy)), Values: make(map[string]any, len(specs))}
+		for _, g := range groupBy {
+			row.Group[g] = derefValue(r[g])
+		}
+		for i, s := range specs {
+			row.Values[s.key] = derefValue(r[fmt.Sprintf("agg_%d", i)])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (b *Builder) parseGroupBy(errs *FilterErrors) []string {
+	var fields []string
+	for _, f := range strings.Split(b.values.Get("group_by"), ",") {
+		field := strings.TrimSpace(f)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(b.allowedGroupBy, field) {
+			errs.Add(NewGroupByNotAllowedError(field, b.allowedGroupBy).at("group_by"))
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func (b *Builder) parseAggregates(errs *FilterErrors) []aggregateSpec {
+	param := b.values.Get("aggregate")
+	if strings.TrimSpace(param) == "" {
+		param = string(AggCount)
+	}
+
+	var specs []aggregateSpec
+	for _, item := range strings.Split(param, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		spec, ok := parseAggregateSpec(item)
+		if !ok || (spec.field != "" && !slices.Contains(b.aggregates[spec.field], spec.fn)) {
+			errs.Add(NewAggregateNotAllowedError(item, b.allowedAggregates()).at("aggregate"))
+			continue
+		}
+		if !slices.ContainsFunc(specs, func(s aggregateSpec) bool { return s.key == spec.key }) {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// allowedAggregates lists every accepted aggregate, for error suggestions.
+func (b *Builder) allowedAggregates() []string {
+	allowed := []string{string(AggCount)}
+	fields := make([]string, 0, len(b.aggregates))
+	for field := range b.aggregates {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		for _, fn := range b.aggregates[field] {
+			allowed = append(allowed, fmt.Sprintf("%s(%s)", fn, field))
+		}
+	}
+	return allowed
+}
+
+type havingCondition struct {
+	sql  string
+	args []any
+}
+
+// parseHaving turns having[aggregate][op]=value into HAVING conditions. The
+// aggregate must be one of the requested ones; only comparisons are allowed.
+func (b *Builder) parseHaving(specs []aggregateSpec, errs *FilterErrors) []havingCondition {
+	parsed := NewParser(b.values).WithPrefix("having").Parse()
+	errs.Merge(parsed.Errors)
+
+	// Map iteration order is random; keep the SQL stable.
+	sort.Slice(parsed.Filters, func(i, j int) bool {
+		return parsed.Filters[i].Field+string(parsed.Filters[i].Operator) < parsed.Filters[j].Field+string(parsed.Filters[j].Operator)
+	})
+
+	validator := NewValidator(nil, nil)
+	var conds []havingCondition
+	for _, f := range parsed.Filters {
+		idx := slices.IndexFunc(specs, func(s aggregateSpec) bool {
+			spec, ok := parseAggregateSpec(f.Field)
+			return ok && s.key == spec.key
+		})
+		if idx < 0 {
+			keys := make([]string, len(specs))
+			for i, s := range specs {
+				keys[i] = s.key
+			}
+			errs.Add(NewHavingNotRequestedError(f.Field, string(f.Operator), fmt.Sprint(f.Value), keys).at(f.Parameter))
+			continue
+		}
+		if err := validator.ValidateFilter(f); err != nil {
+			errs.Add(err.at(f.Parameter))
+			continue
+		}
+
+		expr := specs[idx].expr()
+		value := fmt.Sprint(f.Value)
+		arg := numericArg(value)
+		switch f.Operator {
+		case Equals:
+			conds = append(conds, havingCondition{expr + " = ?", []any{arg}})
+		case NotEquals:
+			conds = append(conds, havingCondition{expr + " <> ?", []any{arg}})
+		case GreaterThan:
+			conds = append(conds, havingCondition{expr + " > ?", []any{arg}})
+		case GreaterThanOrEq:
+			conds = append(conds, havingCondition{expr + " >= ?", []any{arg}})
+		case LessThan:
+			conds = append(conds, havingCondition{expr + " < ?", []any{arg}})
+		case LessThanOrEq:
+			conds = append(conds, havingCondition{expr + " <= ?", []any{arg}})
+		case Between:
+			bounds := strings.Split(value, ",")
+			conds = append(conds, havingCondition{expr + " BETWEEN ? AND ?", []any{numericArg(strings.TrimSpace(bounds[0])), numericArg(strings.TrimSpace(bounds[1]))}})
+		default:
+			errs.Add(NewOperatorNotAllowedError(f.Field, string(f.Operator),
+				[]Clause{Equals, NotEquals, GreaterThan, GreaterThanOrEq, LessThan, LessThanOrEq, Between}).at(f.Parameter))
+		}
+	}
+	return conds
+}
+
+// numericArg binds aggregate comparisons as numbers where possible; some
+// drivers (SQLite) never consider an integer equal to a string.
+func numericArg(value string) any {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// derefValue unwraps the pointers GORM scans nullable model columns into.
+func derefValue(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return v
+	}
+	if rv.IsNil() {
+		return nil
+	}
+	return rv.Elem().Interface()
+}
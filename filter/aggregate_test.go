@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Aggregate(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[age][gte]", "17")
+	q.Set("sort", "-age")
+	q.Set("group_by", "email")
+	q.Set("aggregate", "count,sum(age),MAX(age)")
+	q.Set("having[count][gt]", "1")
+	c := newRequestWithQuery(q)
+
+	rows, errs := NewFromRequest(c, db.Model(&opUser{})).
+		AllowAll("age").
+		AllowGroupBy("email").
+		AllowAggregate("age", AggSum, AggMax).
+		Aggregate()
+	require.Nil(t, errs, "unexpected errors: %+v", errs)
+
+	// alice(20, a@x), ALF(30, a@x), alina(22, b@x), bob(17, null)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "a@x", rows[0].Group["email"])
+	assert.EqualValues(t, 2, rows[0].Values["count"])
+	assert.EqualValues(t, 50, rows[0].Values["sum(age)"])
+	assert.EqualValues(t, 30, rows[0].Values["max(age)"])
+}
+
+func TestBuilder_Aggregate_NoGroupBy(t *testing.T) {
+	db := mustDB(t)
+
+	c := newRequestWithQuery(url.Values{"filter[name][starts-with]": {"al"}})
+	rows, errs := NewFromRequest(c, db.Model(&opUser{})).AllowFields("name").Aggregate()
+	require.Nil(t, errs)
+	require.Len(t, rows, 1)
+	assert.Empty(t, rows[0].Group)
+	assert.EqualValues(t, 3, rows[0].Values["count"])
+}
+
+func TestBuilder_Aggregate_Errors(t *testing.T) {
+	db := mustDB(t)
+
+	cases := map[string]url.Values{
+		"group_by not allowed":  {"group_by": {"name"}},
+		"aggregate not allowed": {"aggregate": {"avg(age)"}},
+		"unknown function":      {"aggregate": {"median(age)"}},
+		"having not requested":  {"having[sum(age)][gt]": {"1"}},
+		"having operator":       {"having[count][like]": {"1"}},
+	}
+	for name, q := range cases {
+		c := newRequestWithQuery(q)
+		_, errs := NewFromRequest(c, db.Model(&opUser{})).
+			AllowGroupBy("email").
+			AllowAggregate("age", AggSum).
+			Aggregate()
+		require.NotNil(t, errs, name)
+		assert.Equal(t, ErrorTypeValidation, errs.First().Type, name)
+	}
+}
@@ -0,0 +1,290 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseAIPFilter parses a Google AIP-160 filter string into a Group.
+//
+// Supported grammar (a pragmatic subset of AIP-160):
+//
+//	expression  = sequence { "AND" sequence }
+//	sequence    = factor { factor }            (implicit AND)
+//	factor      = term { "OR" term }
+//	term        = [ "NOT" | "-" ] simple
+//	simple      = "(" expression ")" | restriction
+//	restriction = member comparator value
+//	comparator  = "=" | "!=" | "<" | "<=" | ">" | ">=" | ":"
+//
+// As specified by AIP-160, OR binds tighter than AND. Values may be bare
+// words/numbers or single/double quoted strings. "=" with leading/trailing
+// '*' wildcards maps to like/starts-with/ends-with, "= null" to null,
+// "field:*" to not-null and "field:value" (has) to like.
+// Global restrictions (a bare value without comparator) are not supported.
+func ParseAIPFilter(input string) (Group, *FilterError) {
+	p := &aipParser{scanner{input: input}}
+	p.skipSpace()
+	if p.eof() {
+		return Group{}, nil
+	}
+	g, err := p.parseExpression()
+	if err != nil {
+		return Group{}, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return Group{}, p.errorAt(p.pos, fmt.Sprintf("unexpected character '%c'", p.peek()))
+	}
+	return g, nil
+}
+
+// ParseAIPOrderBy converts an AIP-132 order_by string ("price desc, name")
+// into the sort syntax understood by the Applier ("-price,name").
+func ParseAIPOrderBy(orderBy string) (string, *FilterError) {
+	return parseDirectionalSort(orderBy, "order_by")
+}
+
+// parseDirectionalSort converts "field [asc|desc], ..." lists (AIP-132
+// order_by, OData $orderby) into the Applier sort syntax. param names the
+// option in error messages.
+func parseDirectionalSort(input, param string) (string, *FilterError) {
+	if strings.TrimSpace(input) == "" {
+		return "", nil
+	}
+
+	specs := make([]string, 0, strings.Count(input, ",")+1)
+	offset := 0
+	for _, part := range strings.Split(input, ",") {
+		column := offset + len(part) - len(strings.TrimLeft(part, " \t")) + 1
+		offset += len(part) + 1
+
+		words := strings.Fields(part)
+		switch {
+		case len(words) == 1:
+			specs = append(specs, words[0])
+		case len(words) == 2 && strings.EqualFold(words[1], "desc"):
+			specs = append(specs, "-"+words[0])
+		case len(words) == 2 && strings.EqualFold(words[1], "asc"):
+			specs = append(specs, words[0])
+		case len(words) == 0:
+			return "", NewSyntaxError(input, column, fmt.Sprintf("empty %s field", param))
+		default:
+			return "", NewSyntaxError(input, column, fmt.Sprintf("invalid %s clause '%s', expected 'field [asc|desc]'", param, strings.TrimSpace(part)))
+		}
+	}
+	return strings.Join(specs, ","), nil
+}
+
+type aipParser struct {
+	scanner
+}
+
+func (p *aipParser) parseExpression() (Group, *FilterError) {
+	first, err := p.parseSequence()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for {
+		p.skipSpace()
+		if !p.acceptKeyword("AND", false) {
+			break
+		}
+		next, err := p.parseSequence()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(And, terms), nil
+}
+
+func (p *aipParser) parseSequence() (Group, *FilterError) {
+	first, err := p.parseFactor()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek() == ')' || p.atKeyword("AND") || p.atKeyword("OR") {
+			break
+		}
+		next, err := p.parseFactor()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(And, terms), nil
+}
+
+func (p *aipParser) parseFactor() (Group, *FilterError) {
+	first, err := p.parseTerm()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for {
+		p.skipSpace()
+		if !p.acceptKeyword("OR", false) {
+			break
+		}
+		next, err := p.parseTerm()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(Or, terms), nil
+}
+
+func (p *aipParser) parseTerm() (Group, *FilterError) {
+	p.skipSpace()
+	negate := false
+	switch {
+	case p.acceptKeyword("NOT", false):
+		negate = true
+	case p.peek() == '-' && p.pos+1 < len(p.input) && !isSpace(p.input[p.pos+1]):
+		p.pos++
+		negate = true
+	}
+
+	g, err := p.parseSimple()
+	if err != nil {
+		return Group{}, err
+	}
+	if negate {
+		g = negateGroup(g)
+	}
+	return g, nil
+}
+
+func (p *aipParser) parseSimple() (Group, *FilterError) {
+	p.skipSpace()
+	if p.eof() {
+		return Group{}, p.errorAt(p.pos, "unexpected end of filter, expected a restriction")
+	}
+	if p.peek() == '(' {
+		p.pos++
+		g, err := p.parseExpression()
+		if err != nil {
+			return Group{}, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return Group{}, p.errorAt(p.pos, "expected ')'")
+		}
+		p.pos++
+		return g, nil
+	}
+	return p.parseRestriction()
+}
+
+func (p *aipParser) parseRestriction() (Group, *FilterError) {
+	memberPos := p.pos
+	member := p.readWhile(isAIPText)
+	if member == "" {
+		return Group{}, p.errorAt(memberPos, fmt.Sprintf("unexpected character '%c'", p.peek()))
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op := p.readComparator()
+	if op == "" {
+		return Group{}, p.errorAt(opPos, fmt.Sprintf("expected comparator after '%s' (global restrictions are not supported)", member))
+	}
+
+	p.skipSpace()
+	valuePos := p.pos
+	value, quoted, err := p.readArg()
+	if err != nil {
+		return Group{}, err
+	}
+
+	f, ok := aipFilter(member, op, value, quoted)
+	if !ok {
+		ferr := p.errorAt(valuePos, fmt.Sprintf("unsupported value for comparator '%s'", op))
+		ferr.Field = member
+		ferr.Operator = op
+		return Group{}, ferr
+	}
+	return Group{Logic: And, Filters: []Filter{f}}, nil
+}
+
+func (p *aipParser) readComparator() string {
+	rest := p.input[p.pos:]
+	for _, c := range []string{"<=", ">=", "!=", "<", ">", "=", ":"} {
+		if strings.HasPrefix(rest, c) {
+			p.pos += len(c)
+			return c
+		}
+	}
+	return ""
+}
+
+func (p *aipParser) readArg() (string, bool, *FilterError) {
+	if q := p.peek(); q == '"' || q == '\'' {
+		v, err := p.readQuoted(false)
+		return v, true, err
+	}
+	pos := p.pos
+	v := p.readWhile(isAIPText)
+	if v == "" {
+		if p.peek() == '(' {
+			return "", false, p.errorAt(pos, "composite values are not supported")
+		}
+		return "", false, p.errorAt(pos, "expected value")
+	}
+	return v, false, nil
+}
+
+// atKeyword reports whether keyword starts at the cursor without consuming it.
+func (p *aipParser) atKeyword(keyword string) bool {
+	pos := p.pos
+	ok := p.acceptKeyword(keyword, false)
+	p.pos = pos
+	return ok
+}
+
+func isAIPText(c byte) bool {
+	return !isSpace(c) && strings.IndexByte(`()<>=!:,"'`, c) < 0
+}
+
+// aipFilter maps an AIP-160 restriction onto a Filter.
+func aipFilter(field, op, value string, quoted bool) (Filter, bool) {
+	isNull := !quoted && value == "null"
+	switch op {
+	case "=":
+		if isNull {
+			return Filter{Field: field, Operator: IsNull, Value: ""}, true
+		}
+		if wc, inner := wildcardClause(value); wc != "" {
+			return Filter{Field: field, Operator: wc, Value: inner}, true
+		}
+		return Filter{Field: field, Operator: Equals, Value: value}, true
+	case "!=":
+		if isNull {
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, true
+		}
+		if wc, inner := wildcardClause(value); wc == Contains {
+			return Filter{Field: field, Operator: NotContains, Value: inner}, true
+		}
+		return Filter{Field: field, Operator: NotEquals, Value: value}, true
+	case "<":
+		return Filter{Field: field, Operator: LessThan, Value: value}, !isNull
+	case "<=":
+		return Filter{Field: field, Operator: LessThanOrEq, Value: value}, !isNull
+	case ">":
+		return Filter{Field: field, Operator: GreaterThan, Value: value}, !isNull
+	case ">=":
+		return Filter{Field: field, Operator: GreaterThanOrEq, Value: value}, !isNull
+	case ":":
+		if !quoted && value == "*" {
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, true
+		}
+		return Filter{Field: field, Operator: Contains, Value: value}, !isNull
+	}
+	return Filter{}, false
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAIPFilter_Precedence(t *testing.T) {
+	g, err := ParseAIPFilter(`price > 10 AND (status = "active" OR owner = "me")`)
+	require.Nil(t, err)
+
+	assert.Equal(t, And, g.Logic)
+	assert.Equal(t, []Filter{{Field: "price", Operator: GreaterThan, Value: "10"}}, g.Filters)
+	require.Len(t, g.Groups, 1)
+	assert.Equal(t, Or, g.Groups[0].Logic)
+	assert.Len(t, g.Groups[0].Filters, 2)
+
+	// OR binds tighter than AND without parentheses, too.
+	g, err = ParseAIPFilter(`a = 1 AND b = 2 OR c = 3`)
+	require.Nil(t, err)
+	assert.Equal(t, And, g.Logic)
+	require.Len(t, g.Groups, 1)
+	assert.Equal(t, Or, g.Groups[0].Logic)
+}
+
+func TestParseAIPFilter_Operators(t *testing.T) {
+	cases := map[string]Filter{
+		`name = "ali*"`:                     {Field: "name", Operator: StartsWith, Value: "ali"},
+		`name != 'bob'`:                     {Field: "name", Operator: NotEquals, Value: "bob"},
+		`email = null`:                      {Field: "email", Operator: IsNull, Value: ""},
+		`email:*`:                           {Field: "email", Operator: IsNotNull, Value: ""},
+		`name:li`:                           {Field: "name", Operator: Contains, Value: "li"},
+		`age<=20`:                           {Field: "age", Operator: LessThanOrEq, Value: "20"},
+		`author.name = x`:                   {Field: "author.name", Operator: Equals, Value: "x"},
+		`created >= "2025-01-01T00:00:00Z"`: {Field: "created", Operator: GreaterThanOrEq, Value: "2025-01-01T00:00:00Z"},
+	}
+	for in, want := range cases {
+		g, err := ParseAIPFilter(in)
+		require.Nil(t, err, in)
+		assert.Equal(t, []Filter{want}, g.Filters, in)
+	}
+}
+
+func TestParseAIPFilter_NotAndImplicitAnd(t *testing.T) {
+	g, err := ParseAIPFilter(`age > 18 NOT name = "bob" -email:*`)
+	require.Nil(t, err)
+	assert.Equal(t, And, g.Logic)
+	assert.Len(t, g.Filters, 1)
+	require.Len(t, g.Groups, 2)
+	assert.True(t, g.Groups[0].Not)
+	assert.True(t, g.Groups[1].Not)
+}
+
+func TestParseAIPFilter_Errors(t *testing.T) {
+	cases := map[string]int{
+		`price`:            6,
+		`price > `:         9,
+		`(a = 1`:           7,
+		`a = "open`:        5,
+		`a = 1 AND`:        10,
+		`a = 1 AND b = 2)`: 16,
+		`tags:(a b)`:       6,
+	}
+	for in, col := range cases {
+		_, err := ParseAIPFilter(in)
+		require.NotNil(t, err, in)
+		assert.Equal(t, col, err.Position, in)
+	}
+}
+
+func TestParseAIPOrderBy(t *testing.T) {
+	spec, err := ParseAIPOrderBy("price desc, name,  created_at asc")
+	require.Nil(t, err)
+	assert.Equal(t, "-price,name,created_at", spec)
+
+	_, err = ParseAIPOrderBy("price, name sideways")
+	require.NotNil(t, err)
+	assert.Equal(t, 8, err.Position)
+}
+
+func TestBuilder_WithAIP_NoGin(t *testing.T) {
+	db := mustDB(t)
+
+	res := NewFromValues(nil, db.Model(&opUser{})).
+		AllowAll("name", "age", "email").
+		WithAIP(`age >= 17 AND (name = "al*" OR email = null) NOT name = "beta"`, "age desc").
+		Apply().
+		Result()
+
+	var got []opUser
+	require.True(t, res.OK(), "unexpected errors: %+v", res.Errors)
+	require.NoError(t, res.Query.Find(&got).Error)
+	require.Len(t, got, 4)
+	assert.Equal(t, []string{"ALF", "alina", "alice", "bob"}, []string{got[0].Name, got[1].Name, got[2].Name, got[3].Name})
+}
+
+func TestBuilder_WithAIP_SortNotAllowed(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(nil, db.Model(&opUser{})).
+		AllowFields("name").
+		AllowSorts("name").
+		WithAIP("", "age desc").
+		Apply()
+
+	require.False(t, b.OK())
+	assert.Equal(t, "age", b.GetErrors().First().Field)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Applier handles applying filters and sort to database queries
@@ -17,6 +18,18 @@ func NewApplier(validator *Validator) *Applier {
 	return &Applier{validator: validator}
 }
 
+// checkFilter reports why f cannot be applied: an invalid filter or a field
+// outside the allowlist.
+func (a *Applier) checkFilter(f Filter) *FilterError {
+	if err := a.validateFilter(f); err != nil {
+		return err
+	}
+	if a.validator != nil && !a.validator.IsFilterAllowed(f) {
+		return NewFieldNotAllowedError(f.Field, a.validator.allowedFields)
+	}
+	return nil
+}
+
 func (a *Applier) validateFilter(filter Filter) *FilterError {
 	if a.validator != nil {
 		return a.validator.ValidateFilter(filter)
@@ -51,6 +64,29 @@ func (a *Applier) Apply(q *gorm.DB, filters []Filter, sortParam string, allowedS
 	return res, nil
 }
 
+// ApplyGroup runs a logical filter expression and sort in one shot.
+// Top-level filters of an AND group are applied exactly like Apply;
+// nested groups become parenthesised conditions.
+func (a *Applier) ApplyGroup(q *gorm.DB, g Group, sortParam string, allowedSorts []string) (*Result, error) {
+	res, err := a.applyGroup(q, g)
+	if err != nil && res != nil && !res.OK() {
+		return res, res.Errors
+	}
+
+	db, sortErrs := a.applySort(res.Query, sortParam, allowedSorts)
+	if len(sortErrs) > 0 {
+		for _, e := range sortErrs {
+			res.AddError(e)
+		}
+	}
+
+	res.Query = db
+	if !res.OK() {
+		return res, res.Errors
+	}
+	return res, nil
+}
+
 // --- private helpers ---
 
 // applyFilters applies the provided filters in sequence.
@@ -58,18 +94,14 @@ func (a *Applier) applyFilters(q *gorm.DB, filters []Filter) (*Result, error) {
 	result := NewResult(q)
 
 	for _, f := range filters {
-		if err := a.validateFilter(f); err != nil {
-			result.AddError(err)
-			continue
-		}
-		if a.validator != nil && !a.validator.IsFilterAllowed(f) {
-			result.AddError(NewFieldNotAllowedError(f.Field, a.validator.allowedFields))
+		if err := a.checkFilter(f); err != nil {
+			result.AddError(err.at(f.Parameter))
 			continue
 		}
 
 		newQ, ferr := a.applyFilter(result.Query, f)
 		if ferr != nil {
-			result.AddError(ferr)
+			result.AddError(ferr.at(f.Parameter))
 			continue
 		}
 		result.Query = newQ
@@ -81,6 +113,82 @@ func (a *Applier) applyFilters(q *gorm.DB, filters []Filter) (*Result, error) {
 	return result, nil
 }
 
+// applyGroup applies a logical expression to q.
+func (a *Applier) applyGroup(q *gorm.DB, g Group) (*Result, error) {
+	var result *Result
+	if g.Logic == Or || g.Not {
+		result = NewResult(q)
+		cond, errs := a.buildGroup(q, g)
+		result.AddErrors(errs...)
+		if cond != nil {
+			result.Query = q.Where(cond)
+		}
+	} else {
+		result, _ = a.applyFilters(q, g.Filters)
+		for _, sub := range g.Groups {
+			cond, errs := a.buildGroup(result.Query, sub)
+			result.AddErrors(errs...)
+			if cond != nil {
+				result.Query = result.Query.Where(cond)
+			}
+		}
+	}
+
+	if !result.OK() {
+		return result, result.Errors
+	}
+	return result, nil
+}
+
+// buildGroup renders a group as a standalone condition that can be passed to
+// Where. It returns nil when the group is empty or invalid.
+func (a *Applier) buildGroup(q *gorm.DB, g Group) (*gorm.DB, []*FilterError) {
+	var errs []*FilterError
+	parts := make([]*gorm.DB, 0, len(g.Filters)+len(g.Groups))
+
+	for _, f := range g.Filters {
+		if err := a.validateFilter(f); err != nil {
+			errs = append(errs, err.at(f.Parameter))
+			continue
+		}
+		cond, ferr := a.applyFilter(q.Session(&gorm.Session{NewDB: true}), f)
+		if ferr != nil {
+			errs = append(errs, ferr.at(f.Parameter))
+			continue
+		}
+		parts = append(parts, cond)
+	}
+	for _, sub := range g.Groups {
+		cond, subErrs := a.buildGroup(q, sub)
+		errs = append(errs, subErrs...)
+		if cond != nil {
+			parts = append(parts, cond)
+		}
+	}
+
+	if len(errs) > 0 || len(parts) == 0 {
+		return nil, errs
+	}
+
+	cond := q.Session(&gorm.Session{NewDB: true})
+	for i, p := range parts {
+		if i > 0 && g.Logic == Or {
+			cond = cond.Or(p)
+		} else {
+			cond = cond.Where(p)
+		}
+	}
+	if g.Not {
+		// gorm's Not() distributes over AND, so wrap the group explicitly.
+		where, _ := cond.Statement.Clauses["WHERE"].Expression.(clause.Where)
+		cond = q.Session(&gorm.Session{NewDB: true}).Where(clause.Expr{
+			SQL:  "NOT (?)",
+			Vars: []any{clause.AndConditions{Exprs: where.Exprs}},
+		})
+	}
+	return cond, nil
+}
+
 // applySort applies a comma-separated sort spec (e.g., "-created_at,name").
 // Pass allowedSorts to restrict which columns can be sorted.
 func (a *Applier) applySort(q *gorm.DB, sortParam string, allowedSorts []string) (*gorm.DB, []*FilterError) {
@@ -174,13 +282,7 @@ func (a *Applier) applyFilter(q *gorm.DB, filter Filter) (*gorm.DB, *FilterError
 	case NotBetween:
 		values := parseCommaSeparatedValues(fmt.Sprintf("%v", value))
 		if len(values) != 2 {
-			return q, NewValidationError(
-				field,
-				string(NotBetween),
-				fmt.Sprintf("%v", value),
-				"Not between operator requires exactly two comma-separated values",
-				"Use format: 'value1,value2' (e.g., '10,20')",
-			)
+			return q, NewInvalidRangeError(field, NotBetween, fmt.Sprintf("%v", value))
 		}
 		q = q.Where(fmt.Sprintf("%s NOT BETWEEN ? AND ?", field), values[0], values[1])
 
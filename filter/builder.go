@@ -1,31 +1,83 @@
 package filter
 
 import (
-	"github.com/gin-gonic/gin"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
 // Builder holds filter configuration and provides a fluent API.
 type Builder struct {
-	ctx           *gin.Context
-	query         *gorm.DB
-	parser        *Parser
-	validator     *Validator
-	applier       *Applier
-	result        *Result
-	allowedFields []string
-	allowedSorts  []string
-	configs       []FilterConfig
-	useConfigs    bool
-}
-
-// New creates a new Builder bound to a Gin context and a base *gorm.DB query.
-func New(c *gin.Context, q *gorm.DB) *Builder {
-	parser := NewParser(c.Request.URL.Query())
+	req             *http.Request
+	reqCtx          context.Context
+	translator      Translator
+	query           *gorm.DB
+	base            *gorm.DB
+	filtered        *gorm.DB
+	parser          *Parser
+	validator       *Validator
+	applier         *Applier
+	result          *Result
+	values          url.Values
+	rsqlParam       string
+	odata           *ODataQuery
+	limits          *Limits
+	mongoDoc        []byte
+	aipFilter       string
+	aipOrderBy      string
+	defaultSort     string
+	mode            Mode
+	errorFormat     ErrorFormat
+	problemTypeBase string
+	resource        string
+	allowedFields   []string
+	allowedSorts    []string
+	allowedSelect   []string
+	allowedIncludes []string
+	allowedGroupBy  []string
+	allowedFacets   []string
+	aggregates      map[string][]AggregateFunc
+	authorize       Authorizer
+	alwaysSelect    []string
+	requiredFields  []string
+	langs           []string
+	selected        []string
+	configs         []FilterConfig
+	scope           []Filter
+	expr            Group
+	estimateAbove   int64
+	maxIncludeDepth int
+	defaultPageSize int
+	maxPageSize     int
+	page            int
+	pageSize        int
+	facetLimit      int
+	facetWorkers    int
+	useConfigs      bool
+	exposeAllowlist bool
+	useOData        bool
+}
+
+// NewFromRequest creates a Builder bound to a request and a base *gorm.DB
+// query. It reads the query string and uses the request for its context,
+// the Accept-Language header, the problem instance and pagination links.
+// For Gin, see ginfilter.New.
+func NewFromRequest(r *http.Request, q *gorm.DB) *Builder {
+	b := NewFromValues(r.URL.Query(), q)
+	b.req = r
+	return b
+}
+
+// NewFromValues creates a Builder from raw query values, without a request.
+// values may be nil when filters come from elsewhere (e.g. WithAIP).
+func NewFromValues(values url.Values, q *gorm.DB) *Builder {
 	return &Builder{
-		ctx:    c,
 		query:  q,
-		parser: parser,
+		parser: NewParser(values),
+		values: values,
 	}
 }
 
@@ -51,6 +103,57 @@ func (b *Builder) AllowAll(fields ...string) *Builder {
 	return b
 }
 
+// AllowSelect sets the allowlist for sparse fieldsets (?fields=id,name).
+// Without it the fields parameter is ignored.
+func (b *Builder) AllowSelect(fields ...string) *Builder {
+	b.allowedSelect = fields
+	return b
+}
+
+// AlwaysSelect sets columns that are selected whenever a sparse fieldset is
+// requested, such as the primary key.
+func (b *Builder) AlwaysSelect(columns ...string) *Builder {
+	b.alwaysSelect = columns
+	return b
+}
+
+// WithResource names the resource type, enabling JSON:API style
+// fields[resource]=... next to the plain fields parameter.
+func (b *Builder) WithResource(name string) *Builder {
+	b.resource = name
+	return b
+}
+
+// AllowIncludes sets the allowlist of association paths for
+// ?include=author,comments.user. Each path is resolved against the model's
+// GORM schema and preloaded. Filters and sorts on "path.field" narrow the
+// preload instead of the parent query; allow them like any other field,
+// e.g. AllowFields("comments.approved").
+func (b *Builder) AllowIncludes(paths ...string) *Builder {
+	b.allowedIncludes = paths
+	return b
+}
+
+// MaxIncludeDepth limits how deeply includes may nest ("a.b.c" has depth 3).
+// Zero means no limit beyond the allowlist.
+func (b *Builder) MaxIncludeDepth(depth int) *Builder {
+	b.maxIncludeDepth = depth
+	return b
+}
+
+// DefaultSort sets the sort used when the request has none, e.g. "-created_at".
+func (b *Builder) DefaultSort(spec string) *Builder {
+	b.defaultSort = spec
+	return b
+}
+
+// RequireFilters rejects requests that do not filter on each of fields.
+// With AllowConfigs, FilterConfig.AsRequired does the same per field.
+func (b *Builder) RequireFilters(fields ...string) *Builder {
+	b.requiredFields = fields
+	return b
+}
+
 // AllowConfigs sets per-field operator allowlists (most precise control).
 func (b *Builder) AllowConfigs(configs ...FilterConfig) *Builder {
 	b.configs = configs
@@ -59,6 +162,61 @@ func (b *Builder) AllowConfigs(configs ...FilterConfig) *Builder {
 	return b
 }
 
+// WithRSQL additionally accepts an RSQL/FIQL expression in the given query
+// parameter (default "filter"), e.g. ?filter=status==active;price=gt=10.
+// It is ANDed with any bracket-syntax filters.
+func (b *Builder) WithRSQL(param string) *Builder {
+	if param == "" {
+		param = "filter"
+	}
+	b.rsqlParam = param
+	return b
+}
+
+// WithAIP sets a Google AIP-160 filter and AIP-132 order_by, as received in
+// the fields of a gRPC List request. A non-empty orderBy replaces ?sort=.
+//
+//	res := filter.NewFromValues(nil, db.Model(&Book{})).
+//		AllowAll("title", "price").
+//		WithAIP(req.GetFilter(), req.GetOrderBy()).
+//		Apply().
+//		Result()
+func (b *Builder) WithAIP(filter, orderBy string) *Builder {
+	b.aipFilter = filter
+	b.aipOrderBy = orderBy
+	return b
+}
+
+// WithCompact additionally accepts the compact single-parameter syntax,
+// e.g. ?where=price:gt:10,status:in:a|b, merged with the bracket filters.
+func (b *Builder) WithCompact(syntax CompactSyntax) *Builder {
+	b.parser.WithCompact(syntax)
+	return b
+}
+
+// WithOData additionally accepts the OData v4 system query options $filter,
+// $orderby, $top, $skip and $count. $filter is ANDed with bracket filters and
+// a non-empty $orderby replaces ?sort=. See OData for the parsed options.
+func (b *Builder) WithOData() *Builder {
+	b.useOData = true
+	return b
+}
+
+// OData returns the OData options parsed by Apply, or nil when WithOData
+// was not used. Handlers read Count from it to decide whether to
+// include a total in the response.
+func (b *Builder) OData() *ODataQuery {
+	return b.odata
+}
+
+// WithMongo additionally applies a MongoDB-style filter document, typically
+// taken from a POST body or a JSON query parameter. It is ANDed with bracket
+// filters and validated against the same allowlists. See ParseMongoFilter.
+func (b *Builder) WithMongo(doc []byte) *Builder {
+	b.mongoDoc = doc
+	return b
+}
+
 // updateValidator rebuilds the validator+applier when allowlists/configs change.
 func (b *Builder) updateValidator() {
 	b.validator = NewValidator(b.allowedFields, b.configs)
@@ -80,32 +238,81 @@ func (b *Builder) Apply() *Builder {
 
 	// Carry parser (format) errors forward.
 	if !parseResult.Errors.OK() {
-		b.result.AddErrors(parseResult.Errors.Errors...)
+		b.reject(parseResult.Errors.Errors...)
 	}
 
-	// Determine allowed sort fields: explicit list or fallback to allowedFields.
-	allowedSorts := b.allowedSorts
-	if allowedSorts == nil {
-		allowedSorts = b.allowedFields
+	// Bracket filters are ANDed with any alternative-syntax expression.
+	expr := b.collectExpression(parseResult.Filters)
+	b.checkScope(expr)
+	b.checkPermissions(expr)
+	sortParam := b.sortSpec()
+	b.checkLimits(expr, sortParam)
+	expr = b.applyDefaults(b.pruneInvalid(expr))
+
+	// Split off filters and sorts that target included associations.
+	var includes []*includeScope
+	if b.allowedIncludes != nil {
+		includes, expr, sortParam = b.resolveIncludes(expr, sortParam)
 	}
 
-	// Pull sort param, e.g. ?sort=-created_at,name
-	sortParam := b.ctx.Query("sort")
+	// Filter stage. It is kept apart from sort, preloads and paging so that
+	// aggregations can run over exactly the filtered set.
+	//
+	// Sessions never mutate their statement, so b.base stays free of client
+	// filters. Scope filters are trusted and skip the validator.
+	b.base = b.query.Session(&gorm.Session{})
+	if len(b.scope) > 0 {
+		scoped, _ := NewApplier(nil).applyFilters(b.base, b.scope)
+		b.result.AddErrors(scoped.Errors.Errors...)
+		b.base = scoped.Query.Session(&gorm.Session{})
+	}
+	// expr is pruned, so facets do not report filters the mode dropped.
+	b.expr = expr
+	res, _ := b.applier.applyGroup(b.base, expr)
+	b.filtered = res.Query
 
-	// Single entrypoint: run filters + sort
-	res, _ := b.applier.Apply(b.query, parseResult.Filters, sortParam, allowedSorts)
+	// Sort on a session so that ORDER BY does not leak into b.filtered.
+	if res.Query != nil {
+		db, sortErrs := b.applier.applySort(res.Query.Session(&gorm.Session{}), sortParam, b.effectiveSorts())
+		for _, err := range sortErrs {
+			res.AddError(err.at(b.sortParam()))
+		}
+		res.Query = db
+	}
 
 	// Merge any applier errors into the builder result
-	if res != nil && !res.OK() {
-		b.result.AddErrors(res.Errors.Errors...)
+	if !res.OK() {
+		b.reject(res.Errors.Errors...)
 	}
 
 	// Update final query
-	if res != nil && res.Query != nil {
-		b.query = res.Query
-		b.result.Query = res.Query
+	if res.Query != nil {
+		b.query = b.applyPreloads(res.Query, includes)
+		if b.allowedSelect != nil {
+			key, fields := fieldsParam(b.values, b.resource)
+			db, selected, errs := b.applier.applySelect(b.query, fields, b.allowedSelect, b.alwaysSelect)
+			for _, err := range errs {
+				b.reject(err.at(key))
+			}
+			b.query = db
+			b.selected = selected
+		}
+		switch {
+		case b.defaultPageSize > 0:
+			b.query = b.applyPage(b.query)
+		case b.odata != nil:
+			if b.odata.Top != nil {
+				b.query = b.query.Limit(*b.odata.Top)
+			}
+			if b.odata.Skip != nil {
+				b.query = b.query.Offset(*b.odata.Skip)
+			}
+		}
+		b.result.Query = b.query
 	}
 
+	b.finishErrors(b.result.Errors.Errors)
+	b.finishErrors(b.result.Warnings)
 	return b
 }
 
@@ -114,6 +321,12 @@ func (b *Builder) Query() *gorm.DB {
 	return b.query
 }
 
+// SelectedFields returns the columns selected by a sparse fieldset, or nil
+// when all columns are selected.
+func (b *Builder) SelectedFields() []string {
+	return b.selected
+}
+
 // Result returns the accumulated result (errors + success flag).
 func (b *Builder) Result() *Result {
 	if b.result == nil {
@@ -134,3 +347,150 @@ func (b *Builder) GetErrors() *FilterErrors {
 	}
 	return b.result.Errors
 }
+
+// effectiveSorts returns the allowed sort fields: the explicit list or the
+// fallback to allowedFields.
+func (b *Builder) effectiveSorts() []string {
+	if b.allowedSorts == nil {
+		return b.allowedFields
+	}
+	return b.allowedSorts
+}
+
+// collectExpression ANDs the bracket-syntax filters with the expressions of
+// every enabled alternative syntax, recording their parse errors.
+func (b *Builder) collectExpression(filters []Filter) Group {
+	expr := Group{Logic: And, Filters: filters}
+
+	if b.rsqlParam != "" {
+		if raw := strings.TrimSpace(b.values.Get(b.rsqlParam)); raw != "" {
+			g, err := ParseRSQL(raw)
+			if err != nil {
+				b.reject(err.at(b.rsqlParam))
+			} else {
+				expr.Groups = append(expr.Groups, withParameter(g, b.rsqlParam))
+			}
+		}
+	}
+
+	if b.aipFilter != "" {
+		g, err := ParseAIPFilter(b.aipFilter)
+		if err != nil {
+			b.reject(err)
+		} else if !g.IsEmpty() {
+			expr.Groups = append(expr.Groups, g)
+		}
+	}
+
+	if b.useOData {
+		odata, errs := ParseOData(b.values)
+		b.reject(errs.Errors...)
+		b.odata = odata
+		if !odata.Filter.IsEmpty() {
+			expr.Groups = append(expr.Groups, odata.Filter)
+		}
+	}
+
+	if len(b.mongoDoc) > 0 {
+		g, errs := ParseMongoFilter(b.mongoDoc)
+		if !errs.OK() {
+			b.reject(errs.Errors...)
+		} else if !g.IsEmpty() {
+			expr.Groups = append(expr.Groups, g)
+		}
+	}
+
+	return expr
+}
+
+// sortSpec returns the sort specification, e.g. "-created_at,name", from
+// ?sort= or from an alternative syntax that replaces it, falling back to
+// DefaultSort.
+func (b *Builder) sortSpec() string {
+	if b.aipOrderBy != "" {
+		spec, err := ParseAIPOrderBy(b.aipOrderBy)
+		if err != nil {
+			b.reject(err)
+		}
+		return spec
+	}
+	if b.odata != nil && b.odata.OrderBy != "" {
+		return b.odata.OrderBy
+	}
+	if spec := b.values.Get("sort"); spec != "" {
+		return spec
+	}
+	return b.defaultSort
+}
+
+// sortParam returns the query key sortSpec reads, for error reporting. AIP
+// order_by is passed in directly and has none.
+func (b *Builder) sortParam() string {
+	switch {
+	case b.aipOrderBy != "":
+		return ""
+	case b.odata != nil && b.odata.OrderBy != "":
+		return "$orderby"
+	default:
+		return "sort"
+	}
+}
+
+// pruneInvalid rejects the filters of g that fail validation and returns g
+// without them. Only the offending filter is removed: its OR or NOT group
+// keeps the remaining terms, and is dropped only when none remain.
+func (b *Builder) pruneInvalid(g Group) Group {
+	out := Group{Logic: g.Logic, Not: g.Not}
+	for _, f := range g.Filters {
+		if err := b.applier.checkFilter(f); err != nil {
+			b.reject(err.at(f.Parameter))
+			continue
+		}
+		out.Filters = append(out.Filters, f)
+	}
+	for _, sub := range g.Groups {
+		if sub = b.pruneInvalid(sub); !sub.IsEmpty() {
+			out.Groups = append(out.Groups, sub)
+		}
+	}
+	return out
+}
+
+// applyDefaults adds the configured default filters for fields the request
+// does not filter on, and reports required fields that are missing. A field
+// counts as filtered when it appears anywhere in expr, including groups, so
+// expr must already be pruned: a required filter dropped by ModeLenient is
+// still missing and fails the request, and a dropped filter does not
+// suppress the default.
+func (b *Builder) applyDefaults(expr Group) Group {
+	present := make(map[string]bool)
+	for _, f := range b.scope {
+		present[f.Field] = true
+	}
+	for _, f := range expr.AllFilters() {
+		present[f.Field] = true
+	}
+
+	for _, field := range b.requiredFields {
+		if !present[field] {
+			b.result.AddError(NewRequiredFilterError(field))
+		}
+	}
+	for _, c := range b.configs {
+		if present[c.Field] {
+			continue
+		}
+		if c.Required {
+			b.result.AddError(NewRequiredFilterError(c.Field))
+			continue
+		}
+		if c.Default != "" {
+			op := c.DefaultOperator
+			if op == "" {
+				op = Equals
+			}
+			expr.Filters = append(expr.Filters, Filter{Field: c.Field, Operator: op, Value: c.Default})
+		}
+	}
+	return expr
+}
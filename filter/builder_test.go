@@ -1,3 +1,5 @@
+//go:build !golens_nogin
+
 package filter
 
 import (
@@ -72,3 +74,39 @@ func TestBuilder_SortRejected(t *testing.T) {
 
 	assert.False(t, b.OK(), "expected builder not OK due to sort rejection")
 }
+
+func TestBuilder_DefaultsAndRequired(t *testing.T) {
+	db := mustDB(t)
+
+	configs := []FilterConfig{
+		AllowedFilter("age", GreaterThanOrEq, LessThan).WithDefault("18"),
+		AllowedFilter("name", Equals, StartsWith).AsRequired(),
+	}
+
+	// name present, age defaulted to >= 18, default sort applies
+	c, _ := newGinCtxWithQuery(url.Values{"filter[name][starts-with]": {"al"}})
+	b := New(c, db.Model(&opUser{})).
+		AllowConfigs(configs...).
+		AllowSorts("age").
+		DefaultSort("-age").
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{"ALF", "alina", "alice"}, []string{got[0].Name, got[1].Name, got[2].Name})
+
+	// a client filter on age replaces the default
+	c, _ = newGinCtxWithQuery(url.Values{"filter[name][starts-with]": {"b"}, "filter[age][lt]": {"18"}})
+	b = New(c, db.Model(&opUser{})).AllowConfigs(configs...).Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 2) // bob (17), beta (10)
+
+	// name is required
+	c, _ = newGinCtxWithQuery(url.Values{})
+	b = New(c, db.Model(&opUser{})).AllowConfigs(configs...).RequireFilters("age").Apply()
+	require.False(t, b.OK())
+	assert.Equal(t, 2, b.GetErrors().Len())
+}
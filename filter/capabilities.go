@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Capabilities is the effective query configuration of an endpoint, for
+// clients that build filter UIs:
+//
+//	{
+//	  "resource": "users",
+//	  "fields": [{"name": "age", "parameter": "filter[age]", "type": "integer",
+//	              "operators": ["gt", "lt"], "default_operator": "gt",
+//	              "description": "Filter by age", "examples": ["filter[age][gt]=10", ...]}],
+//	  "sorts": ["name", "-name"],
+//	  "pagination": {"default_size": 20, "max_size": 100}
+//	}
+//
+// A builder without an allowlist (no AllowFields or AllowConfigs) accepts
+// any field with any operator. It reports "unrestricted": true with empty
+// Fields, and then also accepts any sort unless Sorts lists them.
+type Capabilities struct {
+	Pagination   *PageCapabilities `json:"pagination,omitempty"`
+	Unrestricted bool              `json:"unrestricted,omitempty"`
+	Resource     string            `json:"resource,omitempty"`
+	DefaultSort  string            `json:"default_sort,omitempty"`
+	Fields       []FieldCapability `json:"fields"`
+	Sorts        []string          `json:"sorts"`
+	Includes     []string          `json:"includes,omitempty"`
+	Select       []string          `json:"select,omitempty"`
+	GroupBy      []string          `json:"group_by,omitempty"`
+	Facets       []string          `json:"facets,omitempty"`
+}
+
+// FieldCapability describes one filterable field. Name is the field as used
+// in queries and Parameter its bracket query key.
+type FieldCapability struct {
+	Name            string    `json:"name"`
+	Parameter       string    `json:"parameter"`
+	Description     string    `json:"description,omitempty"`
+	Type            ValueType `json:"type"`
+	DefaultOperator Clause    `json:"default_operator"`
+	Default         string    `json:"default,omitempty"`
+	Operators       []Clause  `json:"operators"`
+	Enum            []string  `json:"enum,omitempty"`
+	Examples        []string  `json:"examples"`
+	Required        bool      `json:"required,omitempty"`
+}
+
+// PageCapabilities holds the page[size] limits set with Paginate.
+type PageCapabilities struct {
+	DefaultSize int `json:"default_size"`
+	MaxSize     int `json:"max_size,omitempty"`
+}
+
+// Capabilities describes what the builder accepts, derived from the same
+// configuration as the Validator. Fields and operators that need a
+// permission the caller lacks (see WithAuthorizer) are left out, as are
+// scoped fields.
+func (b *Builder) Capabilities() *Capabilities {
+	caps := &Capabilities{
+		Unrestricted: len(b.configs) == 0 && len(b.allowedFields) == 0,
+		Resource:     b.resource,
+		DefaultSort:  b.defaultSort,
+		Fields:       []FieldCapability{},
+		Sorts:        sortValues(b.effectiveSorts()),
+		Includes:     b.allowedIncludes,
+		Select:       b.allowedSelect,
+		GroupBy:      b.allowedGroupBy,
+		Facets:       b.allowedFacets,
+	}
+	if b.defaultPageSize > 0 {
+		caps.Pagination = &PageCapabilities{DefaultSize: b.defaultPageSize, MaxSize: b.maxPageSize}
+	}
+
+	for _, spec := range b.fieldSpecs() {
+		cfg := b.fieldConfig(spec.Field)
+		if slices.ContainsFunc(cfg.Permissions, func(p string) bool { return !b.allowed(p) }) {
+			continue
+		}
+		ops := make([]Clause, 0, len(spec.Operators))
+		for _, op := range spec.Operators {
+			if !slices.ContainsFunc(cfg.OperatorPermissions[op], func(p string) bool { return !b.allowed(p) }) {
+				ops = append(ops, op)
+			}
+		}
+		if len(ops) == 0 {
+			continue
+		}
+		spec.Operators = ops
+
+		caps.Fields = append(caps.Fields, FieldCapability{
+			Name:            spec.Field,
+			Parameter:       fmt.Sprintf("filter[%s]", spec.Field),
+			Description:     spec.Description,
+			Type:            spec.Type,
+			DefaultOperator: spec.DefaultOperator,
+			Default:         spec.Default,
+			Operators:       ops,
+			Enum:            spec.Enum,
+			Examples:        filterExamples(spec),
+			Required:        spec.Required,
+		})
+	}
+	return caps
+}
+
+// CapabilitiesHTTPHandler serves the Capabilities of schema applied to
+// query, e.g. mux.Handle("/users/capabilities", filter.CapabilitiesHTTPHandler(db.Model(&User{}), users)).
+// Permissions are resolved against the request. Like HTTPMiddleware, each
+// request works on its own session of query. For Gin, see
+// ginfilter.CapabilitiesHandler.
+func CapabilitiesHTTPHandler(query *gorm.DB, schema Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caps := schema(NewFromRequest(r, query.WithContext(r.Context()))).Capabilities()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(caps)
+	})
+}
+
+// filterExamples returns one example query per operator of spec.
+func filterExamples(spec fieldSpec) []string {
+	value := exampleValue(spec)
+	examples := make([]string, 0, len(spec.Operators))
+	for _, op := range spec.Operators {
+		switch op {
+		case Equals:
+			examples = append(examples, fmt.Sprintf("filter[%s]=%s", spec.Field, value))
+		case In, NotIn, Between, NotBetween:
+			examples = append(examples, fmt.Sprintf("filter[%s][%s]=%s,%s", spec.Field, op, value, value))
+		case IsNull, IsNotNull:
+			examples = append(examples, fmt.Sprintf("filter[%s][%s]=", spec.Field, op))
+		default:
+			examples = append(examples, fmt.Sprintf("filter[%s][%s]=%s", spec.Field, op, value))
+		}
+	}
+	return examples
+}
+
+// exampleValue is a plausible value of spec's type.
+func exampleValue(spec fieldSpec) string {
+	if len(spec.Enum) > 0 {
+		return spec.Enum[0]
+	}
+	switch spec.Type {
+	case TypeInteger:
+		return "10"
+	case TypeNumber:
+		return "9.99"
+	case TypeBoolean:
+		return "true"
+	case TypeDate:
+		return "2025-01-31"
+	case TypeDateTime:
+		return "2025-01-31T12:00:00Z"
+	default:
+		return "abc"
+	}
+}
@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Capabilities(t *testing.T) {
+	db := mustDB(t)
+
+	caps := NewFromValues(nil, db.Model(&opUser{})).
+		AllowConfigs(
+			AllowedFilter("age", GreaterThan, Between),
+			AllowedFilter("name", Equals, In).WithEnum("alice", "bob"),
+		).
+		AllowSorts("name").
+		DefaultSort("-name").
+		Paginate(20, 100).
+		WithResource("users").
+		Capabilities()
+
+	assert.Equal(t, "users", caps.Resource)
+	assert.Equal(t, "-name", caps.DefaultSort)
+	assert.Equal(t, []string{"name", "-name"}, caps.Sorts)
+	assert.Equal(t, &PageCapabilities{DefaultSize: 20, MaxSize: 100}, caps.Pagination)
+
+	require.Len(t, caps.Fields, 2)
+	age := caps.Fields[0]
+	assert.Equal(t, "age", age.Name)
+	assert.Equal(t, "filter[age]", age.Parameter)
+	assert.Equal(t, "Filter by age", age.Description)
+	assert.Equal(t, TypeInteger, age.Type)
+	assert.Equal(t, GreaterThan, age.DefaultOperator)
+	assert.Equal(t, []Clause{GreaterThan, Between}, age.Operators)
+	assert.Equal(t, []string{"filter[age][gt]=10", "filter[age][between]=10,10"}, age.Examples)
+
+	name := caps.Fields[1]
+	assert.Equal(t, []string{"alice", "bob"}, name.Enum)
+	assert.Equal(t, []string{"filter[name]=alice", "filter[name][in]=alice,alice"}, name.Examples)
+}
+
+// Without an allowlist Apply accepts any field, which Capabilities must say.
+func TestBuilder_Capabilities_Unrestricted(t *testing.T) {
+	db := mustDB(t)
+
+	caps := NewFromValues(nil, db.Model(&opUser{})).Capabilities()
+	assert.True(t, caps.Unrestricted)
+	assert.Empty(t, caps.Fields)
+	assert.Empty(t, caps.Sorts)
+	b := NewFromValues(url.Values{"filter[email][like]": {"x"}, "sort": {"age"}}, db.Model(&opUser{})).Apply()
+	assert.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	raw, err := json.Marshal(NewFromValues(nil, db.Model(&opUser{})).AllowFields("name").Capabilities())
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "unrestricted")
+}
+
+func TestBuilder_Capabilities_Permissions(t *testing.T) {
+	db := mustDB(t)
+
+	configs := []FilterConfig{
+		AllowedFilter("name", Equals),
+		AllowedFilter("email", Equals, Contains).
+			RequirePermissions("users:read-email").
+			RequireOperatorPermissions(Contains, "users:search-email"),
+	}
+	fields := func(role string) map[string][]Clause {
+		ctx := context.WithValue(context.Background(), roleKey{}, role)
+		caps := NewFromValues(nil, db.Model(&opUser{})).
+			AllowConfigs(configs...).
+			WithAuthorizer(roleAuthorizer).
+			WithContext(ctx).
+			Capabilities()
+		out := map[string][]Clause{}
+		for _, f := range caps.Fields {
+			out[f.Name] = f.Operators
+		}
+		return out
+	}
+
+	assert.Equal(t, map[string][]Clause{"name": {Equals}, "email": {Equals, Contains}}, fields("admin"))
+	assert.Equal(t, map[string][]Clause{"name": {Equals}, "email": {Equals}}, fields("support"))
+	assert.Equal(t, map[string][]Clause{"name": {Equals}}, fields("public"))
+}
+
+func TestCapabilitiesHTTPHandler(t *testing.T) {
+	db := mustDB(t)
+	h := CapabilitiesHTTPHandler(db.Model(&opUser{}), usersSchema)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/capabilities", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var caps Capabilities
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &caps))
+	require.Len(t, caps.Fields, 2)
+	assert.Equal(t, "name", caps.Fields[0].Name)
+	assert.Equal(t, []Clause{StartsWith}, caps.Fields[0].Operators)
+	assert.Equal(t, []string{"filter[name][starts-with]=abc"}, caps.Fields[0].Examples)
+	assert.Equal(t, TypeInteger, caps.Fields[1].Type)
+	assert.Equal(t, []string{"name", "-name"}, caps.Sorts)
+}
+
+// Run with -race: the handler must not share the query's statement.
+func TestCapabilitiesHTTPHandler_Concurrent(t *testing.T) {
+	db := mustDB(t)
+	h := CapabilitiesHTTPHandler(db.Model(&opUser{}), usersSchema)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 8)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/capabilities", nil))
+			codes[i] = w.Code
+		}()
+	}
+	wg.Wait()
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+}
@@ -21,6 +21,13 @@ const (
 	NotBetween      Clause = "not-between"
 )
 
+// allClauses lists every supported operator, in documentation order.
+var allClauses = []Clause{
+	Equals, NotEquals, Contains, NotContains, StartsWith, EndsWith,
+	GreaterThan, GreaterThanOrEq, LessThan, LessThanOrEq,
+	In, NotIn, IsNull, IsNotNull, Between, NotBetween,
+}
+
 func (c Clause) IsValid() bool {
 	switch c {
 	case Equals, NotEquals, Contains, NotContains, StartsWith, EndsWith,
@@ -0,0 +1,140 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CompactSyntax configures the single-parameter filter syntax, e.g.
+//
+//	?where=price:gt:10,status:in:a|b,created_at:between:2025-01-01|2025-02-01
+//
+// Each item is "field:operator:value" or "field:value" (Equals). List values
+// for in/not-in/between use ListSep and cannot contain ','. Zero-valued
+// fields use the defaults.
+//
+// In "field:x", x is read as an operator whenever it names one, so
+// "email:null" is a null check and "status:in" an in filter without values.
+// Use "field:eq:x" to compare against such a value, e.g. "status:eq:null".
+type CompactSyntax struct {
+	Param   string // query parameter, default "where"
+	ItemSep string // between conditions, default ","
+	PartSep string // between field, operator and value, default ":"
+	ListSep string // between list values, default "|"
+}
+
+func (s CompactSyntax) withDefaults() CompactSyntax {
+	if s.Param == "" {
+		s.Param = "where"
+	}
+	if s.ItemSep == "" {
+		s.ItemSep = ","
+	}
+	if s.PartSep == "" {
+		s.PartSep = ":"
+	}
+	if s.ListSep == "" {
+		s.ListSep = "|"
+	}
+	return s
+}
+
+// WithCompact additionally parses the compact single-parameter syntax.
+// Its filters are merged with the bracket filters; the same field and
+// operator given twice with different values is reported as a conflict.
+func (p *Parser) WithCompact(syntax CompactSyntax) *Parser {
+	s := syntax.withDefaults()
+	p.compact = &s
+	return p
+}
+
+// parseCompact appends the compact-syntax filters to res.
+func (p *Parser) parseCompact(res *ParseResult) {
+	s := p.compact
+	raw := strings.TrimSpace(p.queryValues.Get(s.Param))
+	if raw == "" {
+		return
+	}
+
+	// Index what the bracket syntax produced so duplicates can be merged.
+	seen := make(map[string]string, len(res.Filters))
+	for _, f := range res.Filters {
+		seen[conflictKey(f)] = fmt.Sprint(f.Value)
+	}
+
+	for _, item := range strings.Split(raw, s.ItemSep) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		f, err := s.parseItem(item)
+		if err != nil {
+			res.Errors.Add(err.at(s.Param))
+			continue
+		}
+		if !f.Operator.IsValid() {
+			res.Errors.Add(NewInvalidOperatorError(string(f.Operator)).at(s.Param))
+			continue
+		}
+
+		key := conflictKey(f)
+		if prev, dup := seen[key]; dup {
+			if prev != fmt.Sprint(f.Value) {
+				res.Errors.Add(NewConflictingFilterError(f.Field, string(f.Operator), prev, fmt.Sprint(f.Value)).at(s.Param))
+			}
+			continue
+		}
+		seen[key] = fmt.Sprint(f.Value)
+		f.Parameter = s.Param
+		res.Filters = append(res.Filters, f)
+	}
+}
+
+// parseItem splits "field:op:value" / "field:value". When the second part
+// does not look like an operator, everything after the field is the Equals
+// value, so values such as timestamps may contain the part separator; use
+// "field:eq:value" to compare against values like "a:b".
+func (s *CompactSyntax) parseItem(item string) (Filter, *FilterError) {
+	parts := strings.SplitN(item, s.PartSep, 3)
+	field := strings.TrimSpace(parts[0])
+	if field == "" || len(parts) < 2 {
+		return Filter{}, NewInvalidCompactFilterError(s.Param, item)
+	}
+
+	op := Clause(strings.TrimSpace(parts[1]))
+	if !op.IsValid() && (len(parts) < 3 || !isOperatorToken(string(op))) {
+		return Filter{Field: field, Operator: Equals, Value: strings.TrimSpace(item[len(parts[0])+len(s.PartSep):])}, nil
+	}
+
+	value := ""
+	if len(parts) == 3 {
+		value = strings.TrimSpace(parts[2])
+	}
+	switch op {
+	case In, NotIn, Between, NotBetween:
+		// List values are passed on comma-separated, so a ',' in a value
+		// would split it in two when ListSep is not ','.
+		values := strings.Split(value, s.ListSep)
+		if s.ListSep != "," && slices.ContainsFunc(values, func(v string) bool { return strings.Contains(v, ",") }) {
+			return Filter{}, NewParsingError(field, value, fmt.Sprintf("operator '%s' values cannot contain ','", op), nil)
+		}
+		value = strings.Join(values, ",")
+	}
+	return Filter{Field: field, Operator: op, Value: value}, nil
+}
+
+// isOperatorToken reports whether s is shaped like a Clause name.
+func isOperatorToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < 'a' || s[i] > 'z') && s[i] != '-' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func conflictKey(f Filter) string {
+	return f.Field + "\x00" + string(f.Operator)
+}
@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Compact(t *testing.T) {
+	q := url.Values{}
+	q.Set("where", "price:gt:10,status:in:a|b,created_at:between:2025-01-01|2025-02-01,name:bob,ts:2025-01-01T10:00:00,email:null")
+
+	res := NewParser(q).WithCompact(CompactSyntax{}).Parse()
+	require.True(t, res.Errors.OK(), "unexpected parse errors: %+v", res.Errors)
+	assert.ElementsMatch(t, []Filter{
+		{Field: "price", Operator: GreaterThan, Value: "10", Parameter: "where"},
+		{Field: "status", Operator: In, Value: "a,b", Parameter: "where"},
+		{Field: "created_at", Operator: Between, Value: "2025-01-01,2025-02-01", Parameter: "where"},
+		{Field: "name", Operator: Equals, Value: "bob", Parameter: "where"},
+		{Field: "ts", Operator: Equals, Value: "2025-01-01T10:00:00", Parameter: "where"},
+		{Field: "email", Operator: IsNull, Value: "", Parameter: "where"},
+	}, res.Filters)
+}
+
+func TestParser_Compact_CustomSeparators(t *testing.T) {
+	q := url.Values{}
+	q.Set("q", "price~gte~10;tag~in~x,y")
+
+	res := NewParser(q).WithCompact(CompactSyntax{Param: "q", ItemSep: ";", PartSep: "~", ListSep: ","}).Parse()
+	require.True(t, res.Errors.OK(), "unexpected parse errors: %+v", res.Errors)
+	assert.ElementsMatch(t, []Filter{
+		{Field: "price", Operator: GreaterThanOrEq, Value: "10", Parameter: "q"},
+		{Field: "tag", Operator: In, Value: "x,y", Parameter: "q"},
+	}, res.Filters)
+}
+
+func TestParser_Compact_ListValueWithComma(t *testing.T) {
+	q := url.Values{}
+	q.Set("where", "tag:in:a,b|c;name:bob")
+
+	res := NewParser(q).WithCompact(CompactSyntax{ItemSep: ";"}).Parse()
+	require.Equal(t, 1, res.Errors.Len())
+	assert.Equal(t, "tag", res.Errors.First().Field)
+	assert.Equal(t, "where", res.Errors.First().Parameter)
+	assert.Equal(t, []Filter{{Field: "name", Operator: Equals, Value: "bob", Parameter: "where"}}, res.Filters)
+}
+
+// An operator name in the second part is always read as the operator.
+func TestParser_Compact_OperatorPrecedence(t *testing.T) {
+	q := url.Values{}
+	q.Set("where", "email:null,status:in,name:eq:null")
+
+	res := NewParser(q).WithCompact(CompactSyntax{}).Parse()
+	require.True(t, res.Errors.OK(), "unexpected parse errors: %+v", res.Errors)
+	assert.ElementsMatch(t, []Filter{
+		{Field: "email", Operator: IsNull, Value: "", Parameter: "where"},
+		{Field: "status", Operator: In, Value: "", Parameter: "where"},
+		{Field: "name", Operator: Equals, Value: "null", Parameter: "where"},
+	}, res.Filters)
+}
+
+func TestParser_Compact_MergeAndConflicts(t *testing.T) {
+	q := url.Values{}
+	q.Set("filter[price][gt]", "10")
+	q.Set("filter[name]", "bob")
+	q.Set("where", "price:gt:10,name:alice,age:gtt:3,:x")
+
+	res := NewParser(q).WithCompact(CompactSyntax{}).Parse()
+
+	// price:gt:10 duplicates the bracket filter and is merged away
+	assert.Len(t, res.Filters, 2)
+	require.Equal(t, 3, res.Errors.Len())
+
+	var conflict *FilterError
+	for _, e := range res.Errors.Errors {
+		if e.Field == "name" {
+			conflict = e
+		}
+	}
+	require.NotNil(t, conflict)
+	assert.Equal(t, "alice", conflict.Value)
+}
+
+func TestBuilder_WithCompact(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("where", "age:between:18|30,name:starts-with:al")
+	q.Set("filter[age][lt]", "25")
+	c := newRequestWithQuery(q)
+
+	res := NewFromRequest(c, db.Model(&opUser{})).
+		AllowFields("name", "age").
+		WithCompact(CompactSyntax{}).
+		Apply().
+		Result()
+
+	assert.Equal(t, []string{"alice", "alina"}, namesOf(fetch(t, res)))
+}
@@ -4,10 +4,29 @@ import "fmt"
 
 // FilterConfig is optional configuration for a specific field
 type FilterConfig struct {
-	Field            string
-	DefaultOperator  Clause
-	Description      string
+	Field           string
+	DefaultOperator Clause
+	Description     string
+	// Default is applied with DefaultOperator when the client does not
+	// filter on Field at all.
+	Default          string
 	AllowedOperators []Clause
+	// Permissions the caller needs to filter on Field at all, and per
+	// operator on top of that. See Builder.WithAuthorizer.
+	Permissions         []string
+	OperatorPermissions map[Clause][]string
+	// Cost weighs filters on Field against Limits.MaxCost (default 1).
+	Cost int
+	// Required rejects requests that do not filter on Field.
+	Required bool
+	// Indexed allows leading-wildcard patterns on Field when
+	// Limits.RequireIndexForWildcard is set (e.g. a trigram index).
+	Indexed bool
+	// Type and Enum describe the values of Field in generated documentation
+	// (see Builder.OpenAPIParameters). Without Type, the model column's type
+	// is used. Neither is enforced when filtering.
+	Type ValueType
+	Enum []string
 }
 
 func AllowedFilter(field string, operators ...Clause) FilterConfig {
@@ -22,3 +41,47 @@ func AllowedFilter(field string, operators ...Clause) FilterConfig {
 		Description:      fmt.Sprintf("Filter by %s", field),
 	}
 }
+
+// WithDefault returns a copy of c that filters on value when the client
+// does not filter on the field, e.g. status defaults to "active".
+func (c FilterConfig) WithDefault(value string) FilterConfig {
+	c.Default = value
+	return c
+}
+
+// AsRequired returns a copy of c that must be filtered on by every request.
+func (c FilterConfig) AsRequired() FilterConfig {
+	c.Required = true
+	return c
+}
+
+// WithType returns a copy of c whose values are documented as t.
+func (c FilterConfig) WithType(t ValueType) FilterConfig {
+	c.Type = t
+	return c
+}
+
+// WithEnum returns a copy of c whose values are documented as one of values.
+func (c FilterConfig) WithEnum(values ...string) FilterConfig {
+	c.Enum = append([]string(nil), values...)
+	return c
+}
+
+// RequirePermissions returns a copy of c that only callers holding every
+// permission may filter on.
+func (c FilterConfig) RequirePermissions(permissions ...string) FilterConfig {
+	c.Permissions = append(append([]string(nil), c.Permissions...), permissions...)
+	return c
+}
+
+// RequireOperatorPermissions returns a copy of c where op additionally needs
+// every permission, e.g. only admins may use "like" on email.
+func (c FilterConfig) RequireOperatorPermissions(op Clause, permissions ...string) FilterConfig {
+	perms := make(map[Clause][]string, len(c.OperatorPermissions)+1)
+	for k, v := range c.OperatorPermissions {
+		perms[k] = v
+	}
+	perms[op] = append(append([]string(nil), perms[op]...), permissions...)
+	c.OperatorPermissions = perms
+	return c
+}
@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ValueType is the documented type of a filter value.
+type ValueType string
+
+const (
+	TypeString   ValueType = "string"
+	TypeInteger  ValueType = "integer"
+	TypeNumber   ValueType = "number"
+	TypeBoolean  ValueType = "boolean"
+	TypeDate     ValueType = "date"      // RFC 3339 full-date, e.g. 2025-01-31
+	TypeDateTime ValueType = "date-time" // RFC 3339 date-time
+)
+
+// fieldSpec is the effective, documented configuration of one filterable
+// field, as enforced by the Validator.
+type fieldSpec struct {
+	Field           string
+	Description     string
+	Type            ValueType
+	Enum            []string
+	Operators       []Clause
+	DefaultOperator Clause
+	Default         string
+	Required        bool
+}
+
+// fieldSpecs describes every field a client may filter on, in configuration
+// order. Scoped fields are left out. Without configs, every operator is
+// allowed on each field of AllowFields.
+func (b *Builder) fieldSpecs() []fieldSpec {
+	var specs []fieldSpec
+	if len(b.configs) > 0 {
+		for _, c := range b.configs {
+			if b.isScoped(c.Field) {
+				continue
+			}
+			spec := fieldSpec{
+				Field:           c.Field,
+				Description:     c.Description,
+				Type:            c.Type,
+				Enum:            c.Enum,
+				Operators:       c.AllowedOperators,
+				DefaultOperator: c.DefaultOperator,
+				Default:         c.Default,
+				Required:        c.Required || slices.Contains(b.requiredFields, c.Field),
+			}
+			if len(spec.Operators) == 0 {
+				spec.Operators = allClauses
+			}
+			specs = append(specs, spec)
+		}
+	} else {
+		for _, field := range b.allowedFields {
+			if b.isScoped(field) {
+				continue
+			}
+			specs = append(specs, fieldSpec{
+				Field:     field,
+				Operators: allClauses,
+				Required:  slices.Contains(b.requiredFields, field),
+			})
+		}
+	}
+
+	for i := range specs {
+		s := &specs[i]
+		if s.DefaultOperator == "" {
+			s.DefaultOperator = Equals
+		}
+		if s.Type == "" {
+			s.Type = modelFieldType(b.query, s.Field)
+		}
+	}
+	return specs
+}
+
+// modelFieldType returns the type of field on the query's model, following
+// associations for dotted fields ("comments.approved"). It falls back to
+// TypeString when there is no model or no such column.
+func modelFieldType(q *gorm.DB, field string) ValueType {
+	if q == nil || q.Statement == nil || q.Statement.Model == nil {
+		return TypeString
+	}
+	if err := q.Statement.Parse(q.Statement.Model); err != nil {
+		return TypeString
+	}
+
+	sch := q.Statement.Schema
+	segments := strings.Split(field, ".")
+	for _, seg := range segments[:len(segments)-1] {
+		rel := findRelationship(sch, seg)
+		if rel == nil {
+			return TypeString
+		}
+		sch = rel.FieldSchema
+	}
+	f := sch.LookUpField(segments[len(segments)-1])
+	if f == nil {
+		return TypeString
+	}
+	switch f.DataType {
+	case schema.Bool:
+		return TypeBoolean
+	case schema.Int, schema.Uint:
+		return TypeInteger
+	case schema.Float:
+		return TypeNumber
+	case schema.Time:
+		return TypeDateTime
+	default:
+		return TypeString
+	}
+}
+
+// typeSchema is the JSON Schema of a single value of t.
+func typeSchema(t ValueType, enum []string) map[string]any {
+	var s map[string]any
+	switch t {
+	case TypeInteger, TypeNumber, TypeBoolean:
+		s = map[string]any{"type": string(t)}
+	case TypeDate, TypeDateTime:
+		s = map[string]any{"type": "string", "format": string(t)}
+	default:
+		s = map[string]any{"type": "string"}
+	}
+	if len(enum) > 0 {
+		s["enum"] = enum
+	}
+	return s
+}
+
+// operatorSchema is the JSON Schema of the raw query-string value of op on
+// spec's field, i.e. exactly what Parser.Parse and the Validator accept.
+func operatorSchema(spec fieldSpec, op Clause) map[string]any {
+	switch op {
+	case Contains, NotContains, StartsWith, EndsWith:
+		return map[string]any{"type": "string", "minLength": 1}
+	case In, NotIn:
+		return map[string]any{
+			"type":        "string",
+			"minLength":   1,
+			"description": "Comma-separated " + string(spec.Type) + " values",
+		}
+	case Between, NotBetween:
+		return map[string]any{
+			"type":        "string",
+			"pattern":     "^[^,]+,[^,]+$",
+			"description": "Two comma-separated " + string(spec.Type) + " bounds",
+		}
+	case IsNull, IsNotNull:
+		return map[string]any{"type": "string", "description": "Ignored; may be empty"}
+	case Equals, NotEquals:
+		return typeSchema(spec.Type, spec.Enum)
+	default:
+		return typeSchema(spec.Type, nil)
+	}
+}
+
+// sortValues lists every accepted sort item: each field ascending and, with
+// a '-' prefix, descending.
+func sortValues(fields []string) []string {
+	values := make([]string, 0, 2*len(fields))
+	for _, f := range fields {
+		values = append(values, f, "-"+f)
+	}
+	return values
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // ErrorType for grouping error families
@@ -16,6 +17,7 @@ const (
 	ErrorTypeConfiguration ErrorType = "configuration_error"
 	ErrorTypeDatabase      ErrorType = "database_error"
 	ErrorTypeInternal      ErrorType = "internal_error"
+	ErrorTypePermission    ErrorType = "permission_error"
 )
 
 // ErrorCode enumerates machine-friendly codes for routing/i18n
@@ -27,6 +29,68 @@ const (
 	CodeFilterConfig     ErrorCode = "FILTER_CONFIGURATION_ERROR"
 	CodeFilterDatabase   ErrorCode = "FILTER_DATABASE_ERROR"
 	CodeFilterInternal   ErrorCode = "FILTER_INTERNAL_ERROR"
+	CodeFilterPermission ErrorCode = "FILTER_PERMISSION_DENIED"
+	CodeFilterComplexity ErrorCode = "FILTER_COMPLEXITY_LIMIT"
+)
+
+// ErrorReason is a stable, fine-grained sub-code of an ErrorCode, so that
+// clients can tell "field not allowed" from "missing value" without parsing
+// messages. Every constructor below sets one. Together with ErrorCode it keys
+// localized message templates (see Catalog).
+//
+// Each reason is also a sentinel error:
+//
+//	if errs.AnyIs(filter.ReasonMissingValue) { ... }
+//	if errors.Is(err, filter.ReasonPermissionDenied) { ... }
+type ErrorReason string
+
+// Error implements error so reasons can be matched with errors.Is.
+func (r ErrorReason) Error() string {
+	return "filter: " + strings.ToLower(strings.ReplaceAll(string(r), "_", " "))
+}
+
+const (
+	// Generic reasons of the base constructors.
+	ReasonInvalidValue  ErrorReason = "INVALID_VALUE"  // NewValidationError
+	ReasonInvalidFormat ErrorReason = "INVALID_FORMAT" // NewParsingError, NewInvalidFilterFormatError
+	ReasonMisconfigured ErrorReason = "MISCONFIGURED"  // NewConfigurationError
+	ReasonQueryFailed   ErrorReason = "QUERY_FAILED"   // NewDatabaseError
+	ReasonInternal      ErrorReason = "INTERNAL"       // NewInternalError, WrapAsInternalFilterError
+
+	// Filters.
+	ReasonFieldNotAllowed      ErrorReason = "FIELD_NOT_ALLOWED"      // field outside the allowlist
+	ReasonOperatorNotAllowed   ErrorReason = "OPERATOR_NOT_ALLOWED"   // operator not configured for the field
+	ReasonInvalidOperator      ErrorReason = "INVALID_OPERATOR"       // unknown operator
+	ReasonMissingField         ErrorReason = "MISSING_FIELD"          // empty field name
+	ReasonMissingValue         ErrorReason = "MISSING_VALUE"          // empty value
+	ReasonInvalidRange         ErrorReason = "INVALID_RANGE"          // between/not-between without two values
+	ReasonInvalidCompactFilter ErrorReason = "INVALID_COMPACT_FILTER" // malformed compact syntax item
+	ReasonSyntax               ErrorReason = "SYNTAX_ERROR"           // malformed RSQL/AIP/OData expression
+	ReasonConflictingFilter    ErrorReason = "CONFLICTING_FILTER"     // same field/operator with different values
+	ReasonFilterRequired       ErrorReason = "FILTER_REQUIRED"        // required filter missing
+	ReasonPermissionDenied     ErrorReason = "PERMISSION_DENIED"      // caller lacks a permission
+
+	// Sort, fieldsets, includes, aggregation, facets and paging.
+	ReasonSortNotAllowed       ErrorReason = "SORT_NOT_ALLOWED"
+	ReasonSelectNotAllowed     ErrorReason = "SELECT_NOT_ALLOWED"
+	ReasonIncludeNotAllowed    ErrorReason = "INCLUDE_NOT_ALLOWED"
+	ReasonIncludeTooDeep       ErrorReason = "INCLUDE_TOO_DEEP"
+	ReasonIncludeRequired      ErrorReason = "INCLUDE_REQUIRED"        // filter/sort on an include that was not requested
+	ReasonIncludeFilterInGroup ErrorReason = "INCLUDE_FILTER_IN_GROUP" // include filter inside an OR/NOT group
+	ReasonGroupByNotAllowed    ErrorReason = "GROUP_BY_NOT_ALLOWED"
+	ReasonAggregateNotAllowed  ErrorReason = "AGGREGATE_NOT_ALLOWED"
+	ReasonHavingNotRequested   ErrorReason = "HAVING_NOT_REQUESTED" // having[] on an aggregate not in ?aggregate=
+	ReasonFacetNotAllowed      ErrorReason = "FACET_NOT_ALLOWED"
+	ReasonInvalidPage          ErrorReason = "INVALID_PAGE"
+
+	// Complexity limits (CodeFilterComplexity).
+	ReasonTooManyFilters     ErrorReason = "TOO_MANY_FILTERS"
+	ReasonListTooLong        ErrorReason = "LIST_TOO_LONG"
+	ReasonTooManySortKeys    ErrorReason = "TOO_MANY_SORT_KEYS"
+	ReasonGroupTooDeep       ErrorReason = "GROUP_TOO_DEEP"
+	ReasonPatternTooLong     ErrorReason = "PATTERN_TOO_LONG"
+	ReasonWildcardNotIndexed ErrorReason = "WILDCARD_NOT_INDEXED"
+	ReasonCostExceeded       ErrorReason = "COST_EXCEEDED"
 )
 
 // FilterError is a structured error suitable for programmatic handling and JSON output.
@@ -35,16 +99,29 @@ type FilterError struct {
 	InternalErr error `json:"-"` // not serialized
 
 	// Stringy, i18n-friendly fields
-	Type     ErrorType `json:"type"`
-	Message  string    `json:"message"`
-	Field    string    `json:"field,omitempty"`
-	Operator string    `json:"operator,omitempty"`
-	Value    string    `json:"value,omitempty"`
-	Code     ErrorCode `json:"code"`
+	Type     ErrorType   `json:"type"`
+	Message  string      `json:"message"`
+	Field    string      `json:"field,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Value    string      `json:"value,omitempty"`
+	Code     ErrorCode   `json:"code"`
+	Reason   ErrorReason `json:"reason,omitempty"`
+
+	// Extra template parameters beyond Field/Operator/Value, e.g. "Max"
+	Params map[string]any `json:"-"`
 
 	// UX helpers
 	Suggestions []string `json:"suggestions,omitempty"`
 
+	// 1-based column of a syntax error in a single-string expression (RSQL, ...)
+	Position int `json:"position,omitempty"`
+
+	// Query parameter the error refers to, e.g. "filter[price][gt]" or "sort"
+	Parameter string `json:"parameter,omitempty"`
+
+	// Full allowlist behind Suggestions, see Builder.ExposeAllowlist
+	allowed []string
+
 	// Transport concern (kept here for convenience)
 	HTTPStatus int `json:"-"`
 }
@@ -64,6 +141,20 @@ func (e *FilterError) Error() string {
 // Unwrap exposes the wrapped/internal error.
 func (e *FilterError) Unwrap() error { return e.InternalErr }
 
+// at records the query parameter e refers to, unless one is already set.
+func (e *FilterError) at(param string) *FilterError {
+	if e != nil && e.Parameter == "" {
+		e.Parameter = param
+	}
+	return e
+}
+
+// Is matches the error's ErrorReason, e.g. errors.Is(err, ReasonMissingValue).
+func (e *FilterError) Is(target error) bool {
+	r, ok := target.(ErrorReason)
+	return ok && e.Reason != "" && e.Reason == r
+}
+
 // ToJSONResponse renders a single-error payload.
 // Keep signature for backward compatibility.
 func (e *FilterError) ToJSONResponse() map[string]any {
@@ -72,6 +163,9 @@ func (e *FilterError) ToJSONResponse() map[string]any {
 		"message": e.Message,
 		"code":    string(e.Code),
 	}
+	if e.Reason != "" {
+		errObj["reason"] = string(e.Reason)
+	}
 	if e.Field != "" {
 		errObj["field"] = e.Field
 	}
@@ -84,6 +178,12 @@ func (e *FilterError) ToJSONResponse() map[string]any {
 	if len(e.Suggestions) > 0 {
 		errObj["suggestions"] = e.Suggestions
 	}
+	if e.Position > 0 {
+		errObj["position"] = e.Position
+	}
+	if e.Parameter != "" {
+		errObj["parameter"] = e.Parameter
+	}
 	return map[string]any{"error": errObj}
 }
 
@@ -96,6 +196,8 @@ func (e *FilterError) Status() int {
 	switch e.Type {
 	case ErrorTypeValidation, ErrorTypeParsing:
 		return http.StatusBadRequest
+	case ErrorTypePermission:
+		return http.StatusForbidden
 	case ErrorTypeConfiguration, ErrorTypeInternal, ErrorTypeDatabase:
 		return http.StatusInternalServerError
 	default:
@@ -164,13 +266,15 @@ func (fe *FilterErrors) First() *FilterError {
 }
 
 // Status derives an HTTP status for an error list.
-// If mixed types, prefer 400 for client issues else 500.
+// If mixed types, prefer 400 for client issues, then other 4xx (e.g. 403),
+// else 500.
 func (fe *FilterErrors) Status() int {
 	if len(fe.Errors) == 0 {
 		return http.StatusOK
 	}
 	// If any server-side error appears, prefer 500.
 	hasClient := false
+	otherClient := 0
 	for _, e := range fe.Errors {
 		st := e.Status()
 		if st >= 500 {
@@ -178,11 +282,16 @@ func (fe *FilterErrors) Status() int {
 		}
 		if st == http.StatusBadRequest {
 			hasClient = true
+		} else if st >= 400 && otherClient == 0 {
+			otherClient = st
 		}
 	}
 	if hasClient {
 		return http.StatusBadRequest
 	}
+	if otherClient != 0 {
+		return otherClient
+	}
 	return http.StatusInternalServerError
 }
 
@@ -197,6 +306,9 @@ func (fe *FilterErrors) ToJSONResponse() map[string]any {
 			"message": e.Message,
 			"code":    string(e.Code),
 		}
+		if e.Reason != "" {
+			item["reason"] = string(e.Reason)
+		}
 		if e.Field != "" {
 			item["field"] = e.Field
 		}
@@ -209,11 +321,64 @@ func (fe *FilterErrors) ToJSONResponse() map[string]any {
 		if len(e.Suggestions) > 0 {
 			item["suggestions"] = e.Suggestions
 		}
+		if e.Position > 0 {
+			item["position"] = e.Position
+		}
+		if e.Parameter != "" {
+			item["parameter"] = e.Parameter
+		}
 		arr = append(arr, item)
 	}
 	return map[string]any{"errors": arr}
 }
 
+// ToProblem renders an RFC 9457 problem details document (see Problem).
+// typeBase prefixes the per-code type URI (DefaultProblemTypeBase when
+// empty); instance identifies the request, e.g. its URI, and may be empty.
+func (fe *FilterErrors) ToProblem(typeBase, instance string) *Problem {
+	if typeBase == "" {
+		typeBase = DefaultProblemTypeBase
+	}
+	status := fe.Status()
+	typ, title := problemSummary(typeBase, status, fe.Errors)
+	p := &Problem{
+		Type:     typ,
+		Title:    title,
+		Status:   status,
+		Detail:   problemDetail(fe.Errors),
+		Instance: instance,
+		Errors:   make([]ProblemError, 0, len(fe.Errors)),
+	}
+	for _, e := range fe.Errors {
+		p.Errors = append(p.Errors, ProblemError{
+			Detail:      e.Message,
+			Parameter:   e.Parameter,
+			Code:        e.Code,
+			Reason:      e.Reason,
+			Field:       e.Field,
+			Operator:    e.Operator,
+			Value:       e.Value,
+			Suggestions: e.Suggestions,
+			Position:    e.Position,
+		})
+	}
+	return p
+}
+
+// ToJSONAPI renders JSON:API error objects (see JSONAPIError):
+//
+//	{"errors": [{"status": "400", "code": "FILTER_VALIDATION_ERROR",
+//	             "title": "Invalid filter", "detail": "...",
+//	             "source": {"parameter": "filter[price][gt]"},
+//	             "meta": {"reason": "INVALID_VALUE", "field": "price", ...}}]}
+func (fe *FilterErrors) ToJSONAPI() map[string]any {
+	arr := make([]JSONAPIError, 0, len(fe.Errors))
+	for _, e := range fe.Errors {
+		arr = append(arr, e.toJSONAPI())
+	}
+	return map[string]any{"errors": arr}
+}
+
 // Convenience helpers / constructors (backward-compatible)
 
 func NewValidationError(field, operator, value, message string, suggestions ...string) *FilterError {
@@ -224,6 +389,7 @@ func NewValidationError(field, operator, value, message string, suggestions ...s
 		Operator:    operator,
 		Value:       value,
 		Code:        CodeFilterValidation,
+		Reason:      ReasonInvalidValue,
 		HTTPStatus:  http.StatusBadRequest,
 		Suggestions: suggestions,
 	}
@@ -236,6 +402,7 @@ func NewParsingError(field, value, message string, internalErr error) *FilterErr
 		Field:       field,
 		Value:       value,
 		Code:        CodeFilterParsing,
+		Reason:      ReasonInvalidFormat,
 		HTTPStatus:  http.StatusBadRequest,
 		InternalErr: internalErr,
 	}
@@ -246,6 +413,7 @@ func NewConfigurationError(message string, suggestions ...string) *FilterError {
 		Type:        ErrorTypeConfiguration,
 		Message:     message,
 		Code:        CodeFilterConfig,
+		Reason:      ReasonMisconfigured,
 		HTTPStatus:  http.StatusInternalServerError,
 		Suggestions: suggestions,
 	}
@@ -256,6 +424,7 @@ func NewDatabaseError(message string, internalErr error) *FilterError {
 		Type:        ErrorTypeDatabase,
 		Message:     message,
 		Code:        CodeFilterDatabase,
+		Reason:      ReasonQueryFailed,
 		HTTPStatus:  http.StatusInternalServerError,
 		InternalErr: internalErr,
 	}
@@ -266,19 +435,49 @@ func NewInternalError(message string, internalErr error) *FilterError {
 		Type:        ErrorTypeInternal,
 		Message:     message,
 		Code:        CodeFilterInternal,
+		Reason:      ReasonInternal,
 		HTTPStatus:  http.StatusInternalServerError,
 		InternalErr: internalErr,
 	}
 }
 
+// NewPermissionError reports a filter the caller lacks a permission for.
+func NewPermissionError(field, operator, permission string) *FilterError {
+	msg := fmt.Sprintf("Filtering on '%s' requires permission '%s'", field, permission)
+	if operator != "" {
+		msg = fmt.Sprintf("Operator '%s' on '%s' requires permission '%s'", operator, field, permission)
+	}
+	return &FilterError{
+		Type:       ErrorTypePermission,
+		Message:    msg,
+		Field:      field,
+		Operator:   operator,
+		Code:       CodeFilterPermission,
+		HTTPStatus: http.StatusForbidden,
+		Reason:     ReasonPermissionDenied,
+		Params:     map[string]any{"Permission": permission},
+	}
+}
+
+// NewComplexityError reports a request over one of the configured Limits.
+func NewComplexityError(reason ErrorReason, field, operator, message string) *FilterError {
+	err := NewValidationError(field, operator, "", message)
+	err.Code = CodeFilterComplexity
+	err.Reason = reason
+	return err
+}
+
 func NewFieldNotAllowedError(field string, allowedFields []string) *FilterError {
-	// Copy to avoid external slice mutations showing up in error payload
-	suggestions := append([]string(nil), allowedFields...)
-	return NewValidationError(
+	suggestions := suggest(field, allowedFields)
+	err := NewValidationError(
 		field, "", "",
 		fmt.Sprintf("Field '%s' is not allowed for filtering", field),
 		suggestions...,
 	)
+	// Copy to avoid external slice mutations showing up in error payload
+	err.allowed = append([]string(nil), allowedFields...)
+	err.Reason = ReasonFieldNotAllowed
+	return err
 }
 
 func NewOperatorNotAllowedError(field, operator string, allowedOperators []Clause) *FilterError {
@@ -286,58 +485,243 @@ func NewOperatorNotAllowedError(field, operator string, allowedOperators []Claus
 	for i, op := range allowedOperators {
 		suggestions[i] = string(op)
 	}
-	return NewValidationError(
+	err := NewValidationError(
 		field, operator, "",
 		fmt.Sprintf("Operator '%s' is not allowed for field '%s'", operator, field),
 		suggestions...,
 	)
+	err.Reason = ReasonOperatorNotAllowed
+	return err
 }
 
 func NewInvalidOperatorError(operator string) *FilterError {
-	validOperators := []string{
-		string(Equals), string(NotEquals), string(Contains), string(NotContains),
-		string(StartsWith), string(EndsWith), string(GreaterThan), string(GreaterThanOrEq),
-		string(LessThan), string(LessThanOrEq), string(In), string(NotIn),
-		string(IsNull), string(IsNotNull), string(Between), string(NotBetween),
+	validOperators := make([]string, len(allClauses))
+	for i, c := range allClauses {
+		validOperators[i] = string(c)
 	}
-	return NewValidationError(
+	err := NewValidationError(
 		"", operator, "",
 		fmt.Sprintf("Invalid operator '%s'", operator),
-		validOperators...,
+		suggest(operator, validOperators)...,
 	)
+	err.allowed = validOperators
+	err.Reason = ReasonInvalidOperator
+	return err
 }
 
 func NewInvalidFilterFormatError(filterKey, value string) *FilterError {
-	return NewParsingError(
+	err := NewParsingError(
 		"", value,
 		fmt.Sprintf("Invalid filter format: '%s'. Expected format: 'filter[field]' or 'filter[field][operator]'", filterKey),
 		nil,
 	)
+	err.Reason = ReasonInvalidFormat
+	err.Params = map[string]any{"Key": filterKey}
+	return err
+}
+
+// NewSyntaxError reports a malformed single-string expression. column is the
+// 1-based position of the offending character within input.
+func NewSyntaxError(input string, column int, message string) *FilterError {
+	err := NewParsingError(
+		"", input,
+		fmt.Sprintf("Syntax error at column %d: %s", column, message),
+		nil,
+	)
+	err.Position = column
+	err.Reason = ReasonSyntax
+	err.Params = map[string]any{"Detail": message}
+	return err
+}
+
+func NewInvalidCompactFilterError(param, item string) *FilterError {
+	err := NewParsingError(
+		"", item,
+		fmt.Sprintf("Invalid filter '%s' in '%s'. Expected format: 'field:operator:value' or 'field:value'", item, param),
+		nil,
+	)
+	err.Reason = ReasonInvalidCompactFilter
+	err.Params = map[string]any{"Param": param}
+	return err
+}
+
+func NewConflictingFilterError(field, operator, first, second string) *FilterError {
+	err := NewValidationError(
+		field, operator, second,
+		fmt.Sprintf("Conflicting values for field '%s' with operator '%s': '%s' and '%s'", field, operator, first, second),
+		"Specify each field/operator pair only once",
+	)
+	err.Reason = ReasonConflictingFilter
+	err.Params = map[string]any{"First": first, "Second": second}
+	return err
+}
+
+func NewEmptyFieldError(operator, value string) *FilterError {
+	err := NewValidationError("", operator, value, "Field name cannot be empty")
+	err.Reason = ReasonMissingField
+	return err
 }
 
 func NewMissingValueError(field, operator string) *FilterError {
-	return NewValidationError(
+	err := NewValidationError(
 		field, operator, "",
 		"Filter value cannot be empty",
 		"Provide a non-empty value for the filter",
 	)
+	err.Reason = ReasonMissingValue
+	return err
 }
 
 func NewInvalidBetweenValueError(field, value string) *FilterError {
-	return NewValidationError(
-		field, string(Between), value,
-		"Between operator requires exactly two comma-separated values",
+	return NewInvalidRangeError(field, Between, value)
+}
+
+// NewInvalidRangeError reports a between/not-between value that is not
+// exactly two comma-separated values.
+func NewInvalidRangeError(field string, operator Clause, value string) *FilterError {
+	name := "Between"
+	if operator == NotBetween {
+		name = "Not between"
+	}
+	err := NewValidationError(
+		field, string(operator), value,
+		name+" operator requires exactly two comma-separated values",
 		"Use format: 'value1,value2' (e.g., '10,20')",
 	)
+	err.Reason = ReasonInvalidRange
+	return err
 }
 
 func NewSortFieldNotAllowedError(field string, allowedFields []string) *FilterError {
-	suggestions := append([]string(nil), allowedFields...)
-	return NewValidationError(
+	suggestions := suggest(field, allowedFields)
+	err := NewValidationError(
 		field, "", "",
 		fmt.Sprintf("Sort field '%s' is not allowed", field),
 		suggestions...,
 	)
+	err.allowed = append([]string(nil), allowedFields...)
+	err.Reason = ReasonSortNotAllowed
+	return err
+}
+
+func NewSelectFieldNotAllowedError(field string, allowedFields []string) *FilterError {
+	suggestions := suggest(field, allowedFields)
+	err := NewValidationError(
+		field, "", "",
+		fmt.Sprintf("Field '%s' is not allowed in fields", field),
+		suggestions...,
+	)
+	err.allowed = append([]string(nil), allowedFields...)
+	err.Reason = ReasonSelectNotAllowed
+	return err
+}
+
+func NewIncludeNotAllowedError(path string, allowedPaths []string) *FilterError {
+	suggestions := suggest(path, allowedPaths)
+	err := NewValidationError(
+		path, "", "",
+		fmt.Sprintf("Include '%s' is not allowed", path),
+		suggestions...,
+	)
+	err.allowed = append([]string(nil), allowedPaths...)
+	err.Reason = ReasonIncludeNotAllowed
+	return err
+}
+
+func NewIncludeDepthError(path string, maxDepth int) *FilterError {
+	err := NewValidationError(
+		path, "", "",
+		fmt.Sprintf("Include '%s' exceeds the maximum nesting depth of %d", path, maxDepth),
+	)
+	err.Reason = ReasonIncludeTooDeep
+	err.Params = map[string]any{"Max": maxDepth}
+	return err
+}
+
+func NewIncludeRequiredError(field, operator, include string) *FilterError {
+	err := NewValidationError(
+		field, operator, "",
+		fmt.Sprintf("Field '%s' refers to included resource '%s', which was not requested", field, include),
+		fmt.Sprintf("Add include=%s", include),
+	)
+	err.Reason = ReasonIncludeRequired
+	err.Params = map[string]any{"Include": include}
+	return err
+}
+
+func NewIncludeFilterInGroupError(field, operator, value, include string) *FilterError {
+	err := NewValidationError(
+		field, operator, value,
+		fmt.Sprintf("Filters on included resource '%s' cannot be used inside logical groups", include),
+	)
+	err.Reason = ReasonIncludeFilterInGroup
+	err.Params = map[string]any{"Include": include}
+	return err
+}
+
+func NewGroupByNotAllowedError(field string, allowedFields []string) *FilterError {
+	suggestions := suggest(field, allowedFields)
+	err := NewValidationError(
+		field, "", "",
+		fmt.Sprintf("Field '%s' is not allowed in group_by", field),
+		suggestions...,
+	)
+	err.allowed = append([]string(nil), allowedFields...)
+	err.Reason = ReasonGroupByNotAllowed
+	return err
+}
+
+func NewAggregateNotAllowedError(aggregate string, allowed []string) *FilterError {
+	suggestions := suggest(aggregate, allowed)
+	err := NewValidationError(
+		aggregate, "", "",
+		fmt.Sprintf("Aggregate '%s' is not allowed", aggregate),
+		suggestions...,
+	)
+	err.allowed = append([]string(nil), allowed...)
+	err.Reason = ReasonAggregateNotAllowed
+	return err
+}
+
+func NewHavingNotRequestedError(aggregate, operator, value string, requested []string) *FilterError {
+	err := NewValidationError(
+		aggregate, operator, value,
+		fmt.Sprintf("having[%s] does not match a requested aggregate", aggregate),
+		requested...,
+	)
+	err.Reason = ReasonHavingNotRequested
+	return err
+}
+
+func NewFacetNotAllowedError(field string, allowedFields []string) *FilterError {
+	suggestions := suggest(field, allowedFields)
+	err := NewValidationError(
+		field, "", "",
+		fmt.Sprintf("Field '%s' is not allowed in facets", field),
+		suggestions...,
+	)
+	err.allowed = append([]string(nil), allowedFields...)
+	err.Reason = ReasonFacetNotAllowed
+	return err
+}
+
+func NewInvalidPageError(param, value, reason string) *FilterError {
+	err := NewValidationError(
+		param, "", value,
+		fmt.Sprintf("Invalid %s '%s': %s", param, value, reason),
+	)
+	err.Reason = ReasonInvalidPage
+	err.Params = map[string]any{"Detail": reason}
+	return err
+}
+
+func NewRequiredFilterError(field string) *FilterError {
+	err := NewValidationError(
+		field, "", "",
+		fmt.Sprintf("Filter on '%s' is required", field),
+	)
+	err.Reason = ReasonFilterRequired
+	return err
 }
 
 // Helper to combine a generic error into a FilterError when needed.
@@ -346,11 +730,22 @@ func WrapAsInternalFilterError(msg string, err error) *FilterError {
 		Type:        ErrorTypeInternal,
 		Message:     msg,
 		Code:        CodeFilterInternal,
+		Reason:      ReasonInternal,
 		HTTPStatus:  http.StatusInternalServerError,
 		InternalErr: err,
 	}
 }
 
+// Unwrap exposes the individual errors, so errors.Is and errors.As work on
+// the whole list.
+func (fe *FilterErrors) Unwrap() []error {
+	errs := make([]error, len(fe.Errors))
+	for i, e := range fe.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
 // Utility to check if any underlying error matches a target (errors.Is)
 func (fe *FilterErrors) AnyIs(target error) bool {
 	for _, e := range fe.Errors {
@@ -0,0 +1,54 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorReason_Is(t *testing.T) {
+	cases := map[*FilterError]ErrorReason{
+		NewMissingValueError("name", "eq"):                        ReasonMissingValue,
+		NewEmptyFieldError("eq", "x"):                             ReasonMissingField,
+		NewInvalidRangeError("age", NotBetween, "1"):              ReasonInvalidRange,
+		NewFieldNotAllowedError("secret", []string{"name"}):       ReasonFieldNotAllowed,
+		NewPermissionError("email", "eq", "users:read-email"):     ReasonPermissionDenied,
+		NewComplexityError(ReasonCostExceeded, "", "", "too big"): ReasonCostExceeded,
+		NewDatabaseError("boom", errors.New("driver")):            ReasonQueryFailed,
+	}
+	for err, reason := range cases {
+		assert.ErrorIs(t, err, reason, err.Message)
+		assert.NotErrorIs(t, err, ReasonSyntax, err.Message)
+	}
+	assert.Equal(t, "filter: missing value", ReasonMissingValue.Error())
+}
+
+func TestFilterErrors_AnyIs(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[name][eq]", "")
+	q.Set("filter[secret]", "x")
+	b := NewFromValues(q, db.Model(&opUser{})).
+		AllowFields("name").
+		Apply()
+	require.False(t, b.OK())
+
+	errs := b.GetErrors()
+	assert.True(t, errs.AnyIs(ReasonMissingValue))
+	assert.True(t, errs.AnyIs(ReasonFieldNotAllowed))
+	assert.False(t, errs.AnyIs(ReasonPermissionDenied))
+	assert.ErrorIs(t, errs, ReasonFieldNotAllowed)
+
+	body := errs.ToJSONResponse()
+	items, ok := body["errors"].([]map[string]any)
+	require.True(t, ok)
+	var reasons []any
+	for _, item := range items {
+		reasons = append(reasons, item["reason"])
+	}
+	assert.ElementsMatch(t, []any{"MISSING_VALUE", "FIELD_NOT_ALLOWED"}, reasons)
+}
@@ -0,0 +1,188 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFacetLimit   = 20
+	defaultFacetWorkers = 4
+)
+
+// FacetValue is one distinct value of a facet field and the number of rows
+// that have it.
+type FacetValue struct {
+	Value any   `json:"value"`
+	Count int64 `json:"count"`
+}
+
+// Facet holds the value counts of one field, most frequent first. HasMore
+// reports that values beyond the facet limit were left out.
+type Facet struct {
+	Field   string       `json:"field"`
+	Values  []FacetValue `json:"values"`
+	HasMore bool         `json:"has_more"`
+}
+
+// AllowFacets sets the fields that may be requested with ?facets=a,b.
+func (b *Builder) AllowFacets(fields ...string) *Builder {
+	b.allowedFacets = fields
+	return b
+}
+
+// FacetLimit caps the number of distinct values returned per facet
+// (default 20).
+func (b *Builder) FacetLimit(n int) *Builder {
+	b.facetLimit = n
+	return b
+}
+
+// FacetConcurrency caps the number of facet queries run at once (default 4).
+func (b *Builder) FacetConcurrency(n int) *Builder {
+	b.facetWorkers = n
+	return b
+}
+
+// Facets counts the values of each field in ?facets= (default: every
+// allowed facet). Each facet applies every active filter except those on the
+// facet's own field, so that selecting "status=active" still shows the
+// counts of the other statuses. A logical group is dropped for a facet only
+// when all of its filters are on that field.
+//
+// Facets use the same Applier as Apply, so they agree with the list query.
+func (b *Builder) Facets() ([]Facet, *FilterErrors) {
+	if b.result == nil {
+		b.Apply()
+	}
+	if !b.OK() {
+		return nil, b.GetErrors()
+	}
+
+	errs := &FilterErrors{}
+	fields := b.facetFields(errs)
+	b.finishErrors(errs.Errors)
+	if !errs.OK() {
+		return nil, errs
+	}
+
+	limit := b.facetLimit
+	if limit <= 0 {
+		limit = defaultFacetLimit
+	}
+	workers := b.facetWorkers
+	if workers <= 0 {
+		workers = defaultFacetWorkers
+	}
+
+	facets := make([]Facet, len(fields))
+	facetErrs := make([]*FilterError, len(fields))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, field := range fields {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			facets[i], facetErrs[i] = b.facet(field, limit)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range facetErrs {
+		if err != nil {
+			errs.Add(err)
+		}
+	}
+	if !errs.OK() {
+		return nil, errs
+	}
+	return facets, nil
+}
+
+func (b *Builder) facetFields(errs *FilterErrors) []string {
+	param := strings.TrimSpace(b.values.Get("facets"))
+	if param == "" {
+		return b.allowedFacets
+	}
+
+	var fields []string
+	for _, f := range strings.Split(param, ",") {
+		field := strings.TrimSpace(f)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(b.allowedFacets, field) {
+			errs.Add(NewFacetNotAllowedError(field, b.allowedFacets).at("facets"))
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// facet runs the count query of one field.
+func (b *Builder) facet(field string, limit int) (Facet, *FilterError) {
+	res, _ := b.applier.applyGroup(b.base, withoutField(b.expr, field))
+	if !res.OK() {
+		return Facet{}, res.Errors.First()
+	}
+
+	var raw []map[string]any
+	err := res.Query.Session(&gorm.Session{}).
+		Select(fmt.Sprintf("%s AS value, COUNT(*) AS count", field)).
+		Group(field).
+		Order("count DESC").Order(field).
+		Limit(limit + 1).
+		Find(&raw).Error
+	if err != nil {
+		return Facet{}, NewDatabaseError(fmt.Sprintf("Facet query for '%s' failed", field), err)
+	}
+
+	facet := Facet{Field: field, Values: make([]FacetValue, 0, len(raw))}
+	if len(raw) > limit {
+		raw = raw[:limit]
+		facet.HasMore = true
+	}
+	for _, r := range raw {
+		count, _ := derefValue(r["count"]).(int64)
+		facet.Values = append(facet.Values, FacetValue{Value: derefValue(r["value"]), Count: count})
+	}
+	return facet, nil
+}
+
+// withoutField returns g without the top-level filters on field and without
+// subgroups that only filter on field. An OR or negated group is kept or
+// dropped as a whole, since removing one of its terms changes its meaning.
+func withoutField(g Group, field string) Group {
+	if g.Logic == Or || g.Not {
+		if onlyField(g, field) {
+			return Group{Logic: And}
+		}
+		return g
+	}
+
+	out := Group{Logic: g.Logic}
+	for _, f := range g.Filters {
+		if f.Field != field {
+			out.Filters = append(out.Filters, f)
+		}
+	}
+	for _, sub := range g.Groups {
+		if !onlyField(sub, field) {
+			out.Groups = append(out.Groups, sub)
+		}
+	}
+	return out
+}
+
+// onlyField reports whether g has filters and all of them are on field.
+func onlyField(g Group, field string) bool {
+	all := g.AllFilters()
+	return len(all) > 0 && !slices.ContainsFunc(all, func(f Filter) bool { return f.Field != field })
+}
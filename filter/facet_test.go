@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Facets(t *testing.T) {
+	db := mustDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1) // one in-memory database shared by the workers
+
+	q := url.Values{}
+	q.Set("filter[email]", "a@x")
+	q.Set("filter[age][gte]", "18")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowFields("email", "age").
+		AllowFacets("email", "age").
+		FacetLimit(1).
+		FacetConcurrency(2).
+		Apply()
+	facets, errs := b.Facets()
+	require.Nil(t, errs, "unexpected errors: %+v", errs)
+	require.Len(t, facets, 2)
+
+	// email ignores its own filter: a@x (alice, ALF), b@x (alina)
+	assert.Equal(t, "email", facets[0].Field)
+	assert.Equal(t, []FacetValue{{Value: "a@x", Count: 2}}, facets[0].Values)
+	assert.True(t, facets[0].HasMore)
+
+	// age ignores age>=18 but keeps email=a@x: alice 20, ALF 30
+	assert.Equal(t, "age", facets[1].Field)
+	require.Len(t, facets[1].Values, 1)
+	assert.EqualValues(t, 1, facets[1].Values[0].Count)
+	assert.True(t, facets[1].HasMore)
+
+	// the list query is untouched by facets
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	assert.Len(t, got, 2)
+}
+
+func TestBuilder_Facets_DroppedFilters(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[email]", "a@x")
+	q.Set("filter[age][bogus]", "1")
+	q.Set("filter[secret]", "x")
+
+	for _, mode := range []Mode{ModeLenient, ModeWarn} {
+		b := NewFromValues(q, db.Model(&opUser{})).
+			AllowFields("email", "age").
+			AllowFacets("age").
+			WithMode(mode).
+			Apply()
+		require.True(t, b.OK(), mode)
+
+		facets, errs := b.Facets()
+		require.Nil(t, errs, "%s: unexpected errors: %+v", mode, errs)
+		require.Len(t, facets, 1, mode)
+		assert.Len(t, facets[0].Values, 2, mode)
+	}
+}
+
+func TestBuilder_Facets_NotAllowed(t *testing.T) {
+	db := mustDB(t)
+
+	c := newRequestWithQuery(url.Values{"facets": {"name"}})
+	_, errs := NewFromRequest(c, db.Model(&opUser{})).AllowFacets("email").Facets()
+	require.NotNil(t, errs)
+	assert.Equal(t, "name", errs.First().Field)
+}
+
+func TestWithoutField(t *testing.T) {
+	g := Group{Logic: And,
+		Filters: []Filter{{Field: "status", Operator: Equals, Value: "a"}, {Field: "price", Operator: GreaterThan, Value: "1"}},
+		Groups: []Group{
+			{Logic: Or, Filters: []Filter{{Field: "status", Operator: Equals, Value: "b"}, {Field: "status", Operator: Equals, Value: "c"}}},
+			{Logic: Or, Filters: []Filter{{Field: "status", Operator: Equals, Value: "d"}, {Field: "tag", Operator: Equals, Value: "x"}}},
+		},
+	}
+	got := withoutField(g, "status")
+	assert.Equal(t, []Filter{{Field: "price", Operator: GreaterThan, Value: "1"}}, got.Filters)
+	require.Len(t, got.Groups, 1)
+	assert.Equal(t, "tag", got.Groups[0].Filters[1].Field)
+
+	or := Group{Logic: Or, Filters: []Filter{{Field: "status", Operator: Equals, Value: "a"}, {Field: "tag", Operator: Equals, Value: "x"}}}
+	assert.Equal(t, or, withoutField(or, "status"))
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fieldsParam returns the sparse fieldset requested for resource and its
+// query key, preferring the JSON:API form fields[resource]=a,b over the plain
+// fields=a,b.
+func fieldsParam(values url.Values, resource string) (key, fields string) {
+	if resource != "" {
+		key = "fields[" + resource + "]"
+		if v := strings.TrimSpace(values.Get(key)); v != "" {
+			return key, v
+		}
+	}
+	return "fields", strings.TrimSpace(values.Get("fields"))
+}
+
+// applySelect restricts the selected columns to a comma-separated fieldset
+// (e.g. "id,name,price"). Mandatory columns are always selected, and every
+// requested field must be in allowed.
+func (a *Applier) applySelect(q *gorm.DB, fieldsParam string, allowed, mandatory []string) (*gorm.DB, []string, []*FilterError) {
+	if fieldsParam == "" {
+		return q, nil, nil
+	}
+
+	var errs []*FilterError
+	columns := make([]string, 0, len(mandatory)+strings.Count(fieldsParam, ",")+1)
+	for _, m := range mandatory {
+		if !slices.Contains(columns, m) {
+			columns = append(columns, m)
+		}
+	}
+
+	for _, f := range strings.Split(fieldsParam, ",") {
+		field := strings.TrimSpace(f)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			errs = append(errs, NewSelectFieldNotAllowedError(field, allowed))
+			continue
+		}
+		if !slices.Contains(columns, field) {
+			columns = append(columns, field)
+		}
+	}
+
+	if len(errs) > 0 {
+		return q, nil, errs
+	}
+	return q.Select(columns), columns, nil
+}
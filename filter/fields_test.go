@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_SparseFieldset(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("fields", "name, age,name")
+	q.Set("filter[name]", "alice")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowFields("name").
+		AllowSelect("name", "age", "email").
+		AlwaysSelect("id").
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	assert.Equal(t, []string{"id", "name", "age"}, b.SelectedFields())
+
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 1)
+	assert.NotZero(t, got[0].ID)
+	assert.Equal(t, 20, got[0].Age)
+	assert.Nil(t, got[0].Email, "email was not selected")
+}
+
+func TestBuilder_SparseFieldset_JSONAPI(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("fields[users]", "email")
+	q.Set("fields", "age")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		WithResource("users").
+		AllowSelect("name", "age", "email").
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	assert.Equal(t, []string{"email"}, b.SelectedFields())
+}
+
+func TestBuilder_SparseFieldset_NotAllowed(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("fields", "name,password")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowSelect("name", "age").
+		ExposeAllowlist().
+		Apply()
+
+	require.False(t, b.OK())
+	assert.Equal(t, "password", b.GetErrors().First().Field)
+	assert.Equal(t, []string{"name", "age"}, b.GetErrors().First().Suggestions)
+}
@@ -5,4 +5,92 @@ type Filter struct {
 	Value    any    `json:"value"`
 	Field    string `json:"field"`
 	Operator Clause `json:"operator"`
+	// Parameter is the query key the filter was parsed from, e.g.
+	// "filter[price][gt]", so errors can point at it. Empty for filters
+	// built in code.
+	Parameter string `json:"-"`
+}
+
+// Logic is the boolean operator joining the members of a Group.
+type Logic string
+
+const (
+	And Logic = "and"
+	Or  Logic = "or"
+)
+
+// Group is a logical combination of filters and nested groups.
+// The zero value is an empty AND group. Not negates the whole group.
+type Group struct {
+	Logic   Logic    `json:"logic"`
+	Filters []Filter `json:"filters,omitempty"`
+	Groups  []Group  `json:"groups,omitempty"`
+	Not     bool     `json:"not,omitempty"`
+}
+
+// IsEmpty reports whether the group (recursively) holds no filters.
+func (g Group) IsEmpty() bool {
+	if len(g.Filters) > 0 {
+		return false
+	}
+	for _, sub := range g.Groups {
+		if !sub.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// AllFilters returns every filter in the group, depth first.
+func (g Group) AllFilters() []Filter {
+	out := append([]Filter(nil), g.Filters...)
+	for _, sub := range g.Groups {
+		out = append(out, sub.AllFilters()...)
+	}
+	return out
+}
+
+// withParameter sets the Parameter of every filter in g that has none.
+func withParameter(g Group, param string) Group {
+	for i := range g.Filters {
+		if g.Filters[i].Parameter == "" {
+			g.Filters[i].Parameter = param
+		}
+	}
+	for i := range g.Groups {
+		g.Groups[i] = withParameter(g.Groups[i], param)
+	}
+	return g
+}
+
+// joinGroups combines parsed terms under logic, flattening single filters and
+// nested groups that already use the same logic.
+func joinGroups(logic Logic, terms []Group) Group {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	out := Group{Logic: logic}
+	for _, t := range terms {
+		switch {
+		case t.Not:
+			out.Groups = append(out.Groups, t)
+		case len(t.Filters) == 1 && len(t.Groups) == 0:
+			out.Filters = append(out.Filters, t.Filters[0])
+		case t.Logic == logic || (logic == And && t.Logic == ""):
+			out.Filters = append(out.Filters, t.Filters...)
+			out.Groups = append(out.Groups, t.Groups...)
+		default:
+			out.Groups = append(out.Groups, t)
+		}
+	}
+	return out
+}
+
+// negateGroup wraps g in a NOT, cancelling an existing negation.
+func negateGroup(g Group) Group {
+	if g.Logic == "" {
+		g.Logic = And
+	}
+	g.Not = !g.Not
+	return g
 }
@@ -0,0 +1,16 @@
+//go:build !golens_nogin
+
+package filter
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// New creates a new Builder bound to a Gin context and a base *gorm.DB query.
+//
+// Deprecated: use ginfilter.New. New is kept for existing callers; build
+// with -tags golens_nogin to drop it and package filter's Gin dependency.
+func New(c *gin.Context, q *gorm.DB) *Builder {
+	return NewFromRequest(c.Request, q)
+}
@@ -0,0 +1,54 @@
+// Package ginfilter adapts package filter to Gin. Built with -tags
+// golens_nogin, package filter itself only depends on net/http, so services
+// without Gin do not pull it in.
+package ginfilter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vidinfra/golens/filter"
+	"gorm.io/gorm"
+)
+
+// New creates a Builder bound to a Gin context and a base *gorm.DB query.
+func New(c *gin.Context, q *gorm.DB) *filter.Builder {
+	return filter.NewFromRequest(c.Request, q)
+}
+
+// Middleware applies schema to query for every Gin request. When the
+// request is invalid it aborts with FilterErrors.Status() and the body of
+// Builder.ErrorResponse; otherwise the Builder is stored in the request
+// context for the handler:
+//
+//	r.GET("/users", ginfilter.Middleware(db.Model(&User{}), users), func(c *gin.Context) {
+//		var list []User
+//		filter.QueryFromContext(c.Request.Context()).Find(&list)
+//		...
+//	})
+//
+// query is shared between requests; each request runs on its own session
+// bound to the request context.
+func Middleware(query *gorm.DB, schema filter.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b := schema(New(c, query.WithContext(c.Request.Context()))).Apply()
+		if !b.OK() {
+			status, contentType, body := b.ErrorResponse(b.GetErrors())
+			c.Header("Content-Type", contentType)
+			c.AbortWithStatusJSON(status, body)
+			return
+		}
+		c.Request = c.Request.WithContext(filter.NewContext(c.Request.Context(), b))
+		c.Next()
+	}
+}
+
+// CapabilitiesHandler serves the Capabilities of schema applied to query,
+// e.g. r.GET("/users/capabilities", ginfilter.CapabilitiesHandler(db.Model(&User{}), users)).
+// Permissions are resolved against the request. Like Middleware, each
+// request works on its own session of query.
+func CapabilitiesHandler(query *gorm.DB, schema filter.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, schema(New(c, query.WithContext(c.Request.Context()))).Capabilities())
+	}
+}
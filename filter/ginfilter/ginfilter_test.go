@@ -0,0 +1,125 @@
+package ginfilter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vidinfra/golens/filter"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type user struct {
+	Name string `gorm:"column:name"`
+	ID   int    `gorm:"column:id;primaryKey;autoIncrement"`
+	Age  int    `gorm:"column:age"`
+}
+
+func mustDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open sqlite")
+	require.NoError(t, db.AutoMigrate(&user{}), "migrate")
+	require.NoError(t, db.Create(&[]user{
+		{Name: "alice", Age: 20},
+		{Name: "alina", Age: 22},
+		{Name: "bob", Age: 17},
+	}).Error, "seed")
+	return db
+}
+
+func users(b *filter.Builder) *filter.Builder {
+	return b.
+		AllowConfigs(
+			filter.AllowedFilter("name", filter.StartsWith),
+			filter.AllowedFilter("age", filter.GreaterThan),
+		).
+		AllowSorts("name").
+		WithErrorFormat(filter.FormatProblem)
+}
+
+func TestNew(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := mustDB(t)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/users?"+url.Values{"filter[age][gt]": {"18"}}.Encode(), nil)
+
+	b := users(New(c, db.Model(&user{}))).Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	var got []user
+	require.NoError(t, b.Query().Find(&got).Error)
+	assert.Len(t, got, 2)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := mustDB(t)
+
+	r := gin.New()
+	r.GET("/users", Middleware(db.Model(&user{}), users), func(c *gin.Context) {
+		var list []user
+		require.NoError(t, filter.QueryFromContext(c.Request.Context()).Find(&list).Error)
+		c.JSON(http.StatusOK, gin.H{"count": len(list)})
+	})
+
+	// The shared base query must not accumulate conditions across requests.
+	for target, want := range map[string]int{
+		"/users?filter[name][starts-with]=al": 2,
+		"/users?filter[age][gt]=20":           1,
+		"/users":                              3,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, w.Code, target)
+		var body map[string]int
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, want, body["count"], target)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?filter[email]=x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, filter.ProblemContentType, w.Header().Get("Content-Type"))
+	var problem filter.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "/users?filter[email]=x", problem.Instance)
+	assert.Equal(t, "filter[email]", problem.Errors[0].Parameter)
+}
+
+// Run with -race: requests must not share the query's statement.
+func TestCapabilitiesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := mustDB(t)
+
+	r := gin.New()
+	r.GET("/users/capabilities", CapabilitiesHandler(db.Model(&user{}), users))
+
+	var wg sync.WaitGroup
+	bodies := make([]*httptest.ResponseRecorder, 8)
+	for i := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bodies[i] = httptest.NewRecorder()
+			r.ServeHTTP(bodies[i], httptest.NewRequest(http.MethodGet, "/users/capabilities", nil))
+		}()
+	}
+	wg.Wait()
+
+	for _, w := range bodies {
+		require.Equal(t, http.StatusOK, w.Code)
+		var caps filter.Capabilities
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &caps))
+		require.Len(t, caps.Fields, 2)
+		assert.Equal(t, filter.TypeInteger, caps.Fields[1].Type)
+		assert.Equal(t, []string{"name", "-name"}, caps.Sorts)
+	}
+}
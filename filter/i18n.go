@@ -0,0 +1,216 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Translator localizes the message of an error. langs lists the caller's
+// preferred languages, best first (e.g. from Accept-Language). It returns
+// false when it has no translation, in which case the English message stays.
+type Translator interface {
+	Translate(langs []string, err *FilterError) (string, bool)
+}
+
+// Catalog is a Translator backed by text/template message templates per
+// language. A template is looked up by "CODE.REASON", then "REASON", then
+// "CODE", e.g.
+//
+//	{
+//	  "FIELD_NOT_ALLOWED": "Le champ « {{.Field}} » ne peut pas être filtré",
+//	  "INCLUDE_TOO_DEEP": "« {{.Field}} » dépasse la profondeur maximale de {{.Max}}",
+//	  "FILTER_VALIDATION_ERROR": "Filtre invalide"
+//	}
+//
+// Templates see Field, Operator, Value, Position, Code, Reason and Message
+// (the English text) plus the error's Params.
+type Catalog struct {
+	messages map[string]map[string]*template.Template
+	mu       sync.RWMutex
+}
+
+// NewCatalog returns an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]*template.Template)}
+}
+
+// Add parses messages (key → template) for lang, replacing existing keys.
+func (c *Catalog) Add(lang string, messages map[string]string) error {
+	parsed := make(map[string]*template.Template, len(messages))
+	for key, text := range messages {
+		t, err := template.New(key).Parse(text)
+		if err != nil {
+			return fmt.Errorf("catalog %s: message %s: %w", lang, key, err)
+		}
+		parsed[key] = t
+	}
+
+	lang = strings.ToLower(lang)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[lang] == nil {
+		c.messages[lang] = make(map[string]*template.Template, len(parsed))
+	}
+	for key, t := range parsed {
+		c.messages[lang][key] = t
+	}
+	return nil
+}
+
+// LoadJSON adds the messages of a flat JSON object for lang.
+func (c *Catalog) LoadJSON(lang string, r io.Reader) error {
+	var messages map[string]string
+	if err := json.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf("catalog %s: %w", lang, err)
+	}
+	return c.Add(lang, messages)
+}
+
+// LoadTOML adds the messages of a flat TOML document for lang. Keys
+// containing a dot must be quoted ("FILTER_VALIDATION_ERROR.MISSING_VALUE").
+func (c *Catalog) LoadTOML(lang string, r io.Reader) error {
+	var messages map[string]string
+	if err := toml.NewDecoder(r).Decode(&messages); err != nil {
+		return fmt.Errorf("catalog %s: %w", lang, err)
+	}
+	return c.Add(lang, messages)
+}
+
+// Translate implements Translator. Each preferred language is tried as
+// given ("fr-ca") and then by its base language ("fr").
+func (c *Catalog) Translate(langs []string, err *FilterError) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, lang := range langs {
+		lang = strings.ToLower(lang)
+		candidates := []string{lang}
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			candidates = append(candidates, base)
+		}
+		for _, l := range candidates {
+			if t := c.lookup(c.messages[l], err); t != nil {
+				var sb strings.Builder
+				if t.Execute(&sb, templateData(err)) == nil {
+					return sb.String(), true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *Catalog) lookup(messages map[string]*template.Template, err *FilterError) *template.Template {
+	if messages == nil {
+		return nil
+	}
+	keys := []string{string(err.Code)}
+	if err.Reason != "" {
+		keys = []string{string(err.Code) + "." + string(err.Reason), string(err.Reason), string(err.Code)}
+	}
+	for _, k := range keys {
+		if t, ok := messages[k]; ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// templateData exposes the structured fields of err to a template.
+func templateData(err *FilterError) map[string]any {
+	data := map[string]any{
+		"Field":    err.Field,
+		"Operator": err.Operator,
+		"Value":    err.Value,
+		"Position": err.Position,
+		"Code":     string(err.Code),
+		"Reason":   string(err.Reason),
+		"Message":  err.Message,
+	}
+	for k, v := range err.Params {
+		data[k] = v
+	}
+	return data
+}
+
+// WithTranslator localizes error and warning messages. The language comes
+// from WithLanguage or else the request's Accept-Language header.
+func (b *Builder) WithTranslator(t Translator) *Builder {
+	b.translator = t
+	return b
+}
+
+// WithLanguage sets the preferred languages, best first, overriding
+// Accept-Language. Use it with NewFromValues.
+func (b *Builder) WithLanguage(langs ...string) *Builder {
+	b.langs = langs
+	return b
+}
+
+// languages returns the preferred languages of the request.
+func (b *Builder) languages() []string {
+	if len(b.langs) > 0 {
+		return b.langs
+	}
+	if b.req != nil {
+		return parseAcceptLanguage(b.req.Header.Get("Accept-Language"))
+	}
+	return nil
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language
+// header ordered by quality, dropping "*" and q=0.
+func parseAcceptLanguage(header string) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{lang, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	langs := make([]string, len(tags))
+	for i, t := range tags {
+		langs[i] = t.lang
+	}
+	return langs
+}
+
+// translate replaces the messages of errs with their translation.
+func (b *Builder) translate(errs []*FilterError) {
+	if b.translator == nil {
+		return
+	}
+	langs := b.languages()
+	if len(langs) == 0 {
+		return
+	}
+	for _, e := range errs {
+		if msg, ok := b.translator.Translate(langs, e); ok {
+			e.Message = msg
+		}
+	}
+}
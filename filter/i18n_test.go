@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Translate(t *testing.T) {
+	c := NewCatalog()
+	require.NoError(t, c.LoadJSON("fr", strings.NewReader(`{
+		"FIELD_NOT_ALLOWED": "Le champ « {{.Field}} » ne peut pas être filtré",
+		"FILTER_VALIDATION_ERROR": "Filtre invalide"
+	}`)))
+	require.NoError(t, c.LoadTOML("de", strings.NewReader(`
+"FILTER_VALIDATION_ERROR.INCLUDE_TOO_DEEP" = "Include '{{.Field}}' ist tiefer als {{.Max}}"
+`)))
+
+	msg, ok := c.Translate([]string{"fr-CA"}, NewFieldNotAllowedError("secret", nil))
+	require.True(t, ok)
+	assert.Equal(t, "Le champ « secret » ne peut pas être filtré", msg)
+
+	msg, ok = c.Translate([]string{"fr"}, NewMissingValueError("name", "eq"))
+	require.True(t, ok)
+	assert.Equal(t, "Filtre invalide", msg)
+
+	msg, ok = c.Translate([]string{"it", "de"}, NewIncludeDepthError("a.b.c", 2))
+	require.True(t, ok)
+	assert.Equal(t, "Include 'a.b.c' ist tiefer als 2", msg)
+
+	_, ok = c.Translate([]string{"it"}, NewMissingValueError("name", "eq"))
+	assert.False(t, ok)
+
+	assert.Error(t, c.Add("es", map[string]string{"X": "{{.Field"}))
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"de", "fr-CH", "en"}, parseAcceptLanguage("fr-CH;q=0.9, en;q=0.8, de, *;q=0.5, it;q=0"))
+	assert.Empty(t, parseAcceptLanguage(""))
+}
+
+func TestBuilder_WithTranslator(t *testing.T) {
+	db := mustDB(t)
+	c := NewCatalog()
+	require.NoError(t, c.Add("fr", map[string]string{"FIELD_NOT_ALLOWED": "Champ « {{.Field}} » interdit"}))
+
+	ctx := newRequestWithQuery(url.Values{"filter[secret]": {"x"}})
+	ctx.Header.Set("Accept-Language", "fr-FR,fr;q=0.9,en;q=0.5")
+
+	b := NewFromRequest(ctx, db.Model(&opUser{})).AllowFields("name").WithTranslator(c).Apply()
+	require.False(t, b.OK())
+	assert.Equal(t, "Champ « secret » interdit", b.GetErrors().First().Message)
+	assert.Equal(t, ReasonFieldNotAllowed, b.GetErrors().First().Reason)
+
+	// unknown language keeps the English message
+	b = NewFromValues(url.Values{"filter[secret]": {"x"}}, db.Model(&opUser{})).
+		AllowFields("name").WithTranslator(c).WithLanguage("ja").Apply()
+	assert.Equal(t, "Field 'secret' is not allowed for filtering", b.GetErrors().First().Message)
+}
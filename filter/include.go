@@ -0,0 +1,195 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// includeScope is a requested association path together with the filters and
+// sort that target it (filter[comments.approved]=true, sort=-comments.id).
+type includeScope struct {
+	path    string   // as requested, e.g. "comments.user"
+	preload string   // gorm association path, e.g. "Comments.User"
+	sort    []string // sort items with the path prefix stripped
+	filters []Filter // with the path prefix stripped
+}
+
+// resolveIncludes validates ?include= against the allowlist, the maximum
+// depth and the model's GORM schema. Top-level filters and sort items whose
+// field starts with a requested include path are moved out of expr and
+// sortParam into the matching scope, so they narrow the preload rather than
+// the parent query.
+func (b *Builder) resolveIncludes(expr Group, sortParam string) ([]*includeScope, Group, string) {
+	var scopes []*includeScope
+	for _, p := range strings.Split(b.values.Get("include"), ",") {
+		path := strings.TrimSpace(p)
+		if path == "" {
+			continue
+		}
+		if !slices.Contains(b.allowedIncludes, path) {
+			b.reject(NewIncludeNotAllowedError(path, b.allowedIncludes).at("include"))
+			continue
+		}
+		if b.maxIncludeDepth > 0 && strings.Count(path, ".")+1 > b.maxIncludeDepth {
+			b.reject(NewIncludeDepthError(path, b.maxIncludeDepth).at("include"))
+			continue
+		}
+		preload, err := associationPath(b.query, path)
+		if err != nil {
+			b.reject(err.at("include"))
+			continue
+		}
+
+		// Including "comments.user" includes "comments" as well.
+		segments := strings.Split(path, ".")
+		preloads := strings.Split(preload, ".")
+		for i := range segments {
+			sub := strings.Join(segments[:i+1], ".")
+			if !slices.ContainsFunc(scopes, func(s *includeScope) bool { return s.path == sub }) {
+				scopes = append(scopes, &includeScope{path: sub, preload: strings.Join(preloads[:i+1], ".")})
+			}
+		}
+	}
+
+	// Route filters.
+	parent := expr
+	parent.Filters = nil
+	for _, f := range expr.Filters {
+		scope, rest := b.scopeFor(scopes, f.Field)
+		if scope == nil {
+			if inc := b.allowedIncludeFor(f.Field); inc != "" {
+				b.reject(NewIncludeRequiredError(f.Field, string(f.Operator), inc).at(f.Parameter))
+				continue
+			}
+			parent.Filters = append(parent.Filters, f)
+			continue
+		}
+		if err := b.validator.ValidateFilter(f); err != nil {
+			b.reject(err.at(f.Parameter))
+			continue
+		}
+		f.Field = rest
+		scope.filters = append(scope.filters, f)
+	}
+	for _, f := range (Group{Groups: expr.Groups}).AllFilters() {
+		if inc := b.allowedIncludeFor(f.Field); inc != "" {
+			b.reject(NewIncludeFilterInGroupError(f.Field, string(f.Operator), fmt.Sprint(f.Value), inc).at(f.Parameter))
+		}
+	}
+
+	// Route sort items.
+	allowedSorts := b.effectiveSorts()
+	var parentSort []string
+	for _, s := range strings.Split(sortParam, ",") {
+		item := strings.TrimSpace(s)
+		field := strings.TrimPrefix(item, "-")
+		if field == "" {
+			continue
+		}
+		scope, rest := b.scopeFor(scopes, field)
+		if scope == nil {
+			if inc := b.allowedIncludeFor(field); inc != "" {
+				b.reject(NewIncludeRequiredError(field, "", inc).at(b.sortParam()))
+				continue
+			}
+			parentSort = append(parentSort, item)
+			continue
+		}
+		if allowedSorts != nil && !slices.Contains(allowedSorts, field) {
+			b.reject(NewSortFieldNotAllowedError(field, allowedSorts).at(b.sortParam()))
+			continue
+		}
+		scope.sort = append(scope.sort, strings.TrimSuffix(item, field)+rest)
+	}
+
+	return scopes, parent, strings.Join(parentSort, ",")
+}
+
+// scopeFor returns the requested include with the longest path prefixing
+// field, and the remainder of field after that prefix.
+func (b *Builder) scopeFor(scopes []*includeScope, field string) (*includeScope, string) {
+	var best *includeScope
+	for _, s := range scopes {
+		if strings.HasPrefix(field, s.path+".") && (best == nil || len(s.path) > len(best.path)) {
+			best = s
+		}
+	}
+	if best == nil {
+		return nil, field
+	}
+	return best, field[len(best.path)+1:]
+}
+
+// allowedIncludeFor returns the allowed include path that field refers to, if any.
+func (b *Builder) allowedIncludeFor(field string) string {
+	best := ""
+	for _, inc := range b.allowedIncludes {
+		if strings.HasPrefix(field, inc+".") && len(inc) > len(best) {
+			best = inc
+		}
+	}
+	return best
+}
+
+// applyPreloads adds a Preload per scope, narrowed by its filters and sort.
+// Filters were validated in resolveIncludes, so the preload callbacks cannot
+// fail at query time.
+func (b *Builder) applyPreloads(q *gorm.DB, scopes []*includeScope) *gorm.DB {
+	for _, s := range scopes {
+		if len(s.filters) == 0 && len(s.sort) == 0 {
+			q = q.Preload(s.preload)
+			continue
+		}
+		scope := s
+		q = q.Preload(scope.preload, func(db *gorm.DB) *gorm.DB {
+			a := NewApplier(nil)
+			res, _ := a.applyFilters(db, scope.filters)
+			db, _ = a.applySort(res.Query, strings.Join(scope.sort, ","), nil)
+			return db
+		})
+	}
+	return q
+}
+
+// associationPath maps a dotted include path ("comments.user") onto GORM
+// relationship names ("Comments.User") using the query's model schema.
+// Segments match relationship names case-insensitively, ignoring '_'.
+func associationPath(q *gorm.DB, path string) (string, *FilterError) {
+	if q == nil || q.Statement == nil || q.Statement.Model == nil {
+		return "", NewConfigurationError(fmt.Sprintf("Cannot resolve include '%s': query has no model", path))
+	}
+	if err := q.Statement.Parse(q.Statement.Model); err != nil {
+		return "", NewConfigurationError(fmt.Sprintf("Cannot resolve include '%s': %v", path, err))
+	}
+
+	sch := q.Statement.Schema
+	segments := strings.Split(path, ".")
+	names := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		rel := findRelationship(sch, seg)
+		if rel == nil {
+			return "", NewConfigurationError(
+				fmt.Sprintf("Include '%s' does not match an association of %s", path, sch.Name),
+			)
+		}
+		names = append(names, rel.Name)
+		sch = rel.FieldSchema
+	}
+	return strings.Join(names, "."), nil
+}
+
+func findRelationship(sch *schema.Schema, name string) *schema.Relationship {
+	want := strings.ReplaceAll(name, "_", "")
+	sch.Relationships.Mux.RLock()
+	defer sch.Relationships.Mux.RUnlock()
+	for relName, rel := range sch.Relationships.Relations {
+		if !strings.HasPrefix(relName, "_") && strings.EqualFold(relName, want) {
+			return rel
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type incUser struct {
+	Name string `gorm:"column:name"`
+	ID   int    `gorm:"column:id;primaryKey;autoIncrement"`
+}
+
+type incComment struct {
+	User     incUser `gorm:"foreignKey:UserID"`
+	Body     string  `gorm:"column:body"`
+	ID       int     `gorm:"column:id;primaryKey;autoIncrement"`
+	PostID   int     `gorm:"column:post_id"`
+	UserID   int     `gorm:"column:user_id"`
+	Approved bool    `gorm:"column:approved"`
+}
+
+type incPost struct {
+	Title    string       `gorm:"column:title"`
+	Author   incUser      `gorm:"foreignKey:AuthorID"`
+	Comments []incComment `gorm:"foreignKey:PostID"`
+	ID       int          `gorm:"column:id;primaryKey;autoIncrement"`
+	AuthorID int          `gorm:"column:author_id"`
+}
+
+func includeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err, "open sqlite")
+	require.NoError(t, db.AutoMigrate(&incUser{}, &incPost{}, &incComment{}), "migrate")
+
+	ann := incUser{Name: "ann"}
+	ben := incUser{Name: "ben"}
+	require.NoError(t, db.Create(&[]*incUser{&ann, &ben}).Error)
+	post := incPost{Title: "hello", AuthorID: ann.ID, Comments: []incComment{
+		{Body: "first", Approved: true, UserID: ben.ID},
+		{Body: "second", Approved: false, UserID: ann.ID},
+		{Body: "third", Approved: true, UserID: ann.ID},
+	}}
+	require.NoError(t, db.Create(&post).Error, "seed")
+	return db
+}
+
+func TestBuilder_Include(t *testing.T) {
+	db := includeDB(t)
+
+	q := url.Values{}
+	q.Set("include", "author,comments.user")
+	q.Set("filter[title]", "hello")
+	q.Set("filter[comments.approved]", "1")
+	q.Set("sort", "-comments.id")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&incPost{})).
+		AllowFields("title", "comments.approved").
+		AllowSorts("title", "comments.id").
+		AllowIncludes("author", "comments", "comments.user").
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	var posts []incPost
+	require.NoError(t, b.Query().Find(&posts).Error)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "ann", posts[0].Author.Name)
+
+	// comments narrowed and sorted by the scoped filter/sort, users preloaded
+	require.Len(t, posts[0].Comments, 2)
+	assert.Equal(t, "third", posts[0].Comments[0].Body)
+	assert.Equal(t, "ann", posts[0].Comments[0].User.Name)
+	assert.Equal(t, "first", posts[0].Comments[1].Body)
+	assert.Equal(t, "ben", posts[0].Comments[1].User.Name)
+}
+
+func TestBuilder_Include_Errors(t *testing.T) {
+	db := includeDB(t)
+
+	cases := map[string]url.Values{
+		"not allowed":       {"include": {"secrets"}},
+		"too deep":          {"include": {"comments.user"}},
+		"missing include":   {"filter[comments.approved]": {"true"}},
+		"field not allowed": {"include": {"comments"}, "filter[comments.body]": {"x"}},
+	}
+	for name, q := range cases {
+		c := newRequestWithQuery(q)
+		b := NewFromRequest(c, db.Model(&incPost{})).
+			AllowFields("title", "comments.approved").
+			AllowIncludes("comments", "comments.user").
+			MaxIncludeDepth(1).
+			Apply()
+		assert.False(t, b.OK(), name)
+	}
+}
+
+func TestBuilder_Include_UnknownAssociation(t *testing.T) {
+	db := includeDB(t)
+
+	q := url.Values{}
+	q.Set("include", "editor")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&incPost{})).
+		AllowIncludes("editor").
+		Apply()
+
+	require.False(t, b.OK())
+	assert.Equal(t, ErrorTypeConfiguration, b.GetErrors().First().Type)
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// JSONAPIContentType is the JSON:API media type.
+const JSONAPIContentType = "application/vnd.api+json"
+
+// JSONAPIError is a JSON:API error object. Source.Parameter names the query
+// key that caused the error, e.g. "filter[price][gt]" or "sort"; Meta holds
+// the golens specifics (reason, field, operator, value, suggestions,
+// position).
+type JSONAPIError struct {
+	Source *JSONAPISource `json:"source,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
+	Status string         `json:"status"`
+	Code   string         `json:"code"`
+	Title  string         `json:"title"`
+	Detail string         `json:"detail"`
+}
+
+// JSONAPISource points at the origin of a JSONAPIError.
+type JSONAPISource struct {
+	Parameter string `json:"parameter,omitempty"`
+}
+
+func (e *FilterError) toJSONAPI() JSONAPIError {
+	status := e.Status()
+	title, ok := problemTitles[e.Code]
+	if !ok {
+		title = http.StatusText(status)
+	}
+	out := JSONAPIError{
+		Status: strconv.Itoa(status),
+		Code:   string(e.Code),
+		Title:  title,
+		Detail: e.Message,
+	}
+	if e.Parameter != "" {
+		out.Source = &JSONAPISource{Parameter: e.Parameter}
+	}
+
+	meta := map[string]any{}
+	if e.Reason != "" {
+		meta["reason"] = string(e.Reason)
+	}
+	if e.Field != "" {
+		meta["field"] = e.Field
+	}
+	if e.Operator != "" {
+		meta["operator"] = e.Operator
+	}
+	if e.Value != "" {
+		meta["value"] = e.Value
+	}
+	if len(e.Suggestions) > 0 {
+		meta["suggestions"] = e.Suggestions
+	}
+	if e.Position > 0 {
+		meta["position"] = e.Position
+	}
+	if len(meta) > 0 {
+		out.Meta = meta
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_RecordsParameter(t *testing.T) {
+	q := url.Values{}
+	q.Set("filter[name]", "alice")
+	q.Set("filter[age][gtt]", "3")
+
+	res := NewParser(q).Parse()
+	require.Len(t, res.Filters, 1)
+	assert.Equal(t, "filter[name]", res.Filters[0].Parameter)
+	require.Equal(t, 1, res.Errors.Len())
+	assert.Equal(t, "filter[age][gtt]", res.Errors.First().Parameter)
+}
+
+func TestBuilder_ErrorResponse_JSONAPI(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[secret]", "x")
+	q.Set("filter[age][between]", "1")
+	q.Set("where", "name:like:")
+	q.Set("q", "email==x")
+	q.Set("sort", "-nmae")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowConfigs(
+			AllowedFilter("name", Contains),
+			AllowedFilter("age", Between),
+		).
+		AllowSorts("name").
+		WithCompact(CompactSyntax{}).
+		WithRSQL("q").
+		WithErrorFormat(FormatJSONAPI).
+		Apply()
+	require.False(t, b.OK())
+
+	status, contentType, body := b.ErrorResponse(b.GetErrors())
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, JSONAPIContentType, contentType)
+
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	var doc struct {
+		Errors []struct {
+			Status string `json:"status"`
+			Code   string `json:"code"`
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+			Source struct {
+				Parameter string `json:"parameter"`
+			} `json:"source"`
+			Meta map[string]any `json:"meta"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &doc))
+
+	params := map[string]string{}
+	for _, e := range doc.Errors {
+		assert.Equal(t, "400", e.Status)
+		assert.Equal(t, string(CodeFilterValidation), e.Code)
+		assert.Equal(t, "Invalid filter", e.Title)
+		assert.NotEmpty(t, e.Detail)
+		params[e.Meta["reason"].(string)+" "+e.Meta["field"].(string)] = e.Source.Parameter
+	}
+	assert.Equal(t, map[string]string{
+		"FIELD_NOT_ALLOWED secret": "filter[secret]",
+		"INVALID_RANGE age":        "filter[age][between]",
+		"MISSING_VALUE name":       "where",
+		"FIELD_NOT_ALLOWED email":  "q",
+		"SORT_NOT_ALLOWED nmae":    "sort",
+	}, params)
+}
@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+)
+
+// FilterDocumentSchemaVersion versions the layout of FilterDocumentSchema.
+// It changes whenever the generated schema changes shape.
+const FilterDocumentSchemaVersion = "1"
+
+// mongoOperators maps clauses onto the operators of the filter document
+// format (see ParseMongoFilter).
+var mongoOperators = map[Clause]string{
+	Equals:          "$eq",
+	NotEquals:       "$ne",
+	GreaterThan:     "$gt",
+	GreaterThanOrEq: "$gte",
+	LessThan:        "$lt",
+	LessThanOrEq:    "$lte",
+	In:              "$in",
+	NotIn:           "$nin",
+}
+
+// FilterDocumentSchema returns a JSON Schema (draft 2020-12) of the filter
+// documents accepted by WithMongo, restricted to the fields and operators
+// the builder allows:
+//
+//	{"price": {"$gte": 10}, "$or": [{"status": "new"}, {"status": "paid"}]}
+//
+// Every allowed field is a property of a document, either with a bare value
+// (when eq is allowed) or with an operator document listing exactly the
+// allowed operators; $and, $or, $nor and $not nest documents. Value types
+// and descriptions come from the FilterConfigs (see fieldSpecs), so the
+// schema and the Validator agree. The "x-schema-version" member holds
+// FilterDocumentSchemaVersion; id, when not empty, becomes "$id".
+func (b *Builder) FilterDocumentSchema(id string) map[string]any {
+	// Nested documents must not be empty; only the root may match all.
+	sub := map[string]any{"$ref": "#/$defs/document", "minProperties": 1}
+	nested := map[string]any{
+		"type":     "array",
+		"minItems": 1,
+		"items":    sub,
+	}
+	props := map[string]any{
+		"$and": nested,
+		"$or":  nested,
+		"$nor": nested,
+		"$not": sub,
+	}
+	defs := map[string]any{}
+
+	for _, spec := range b.fieldSpecs() {
+		opsRef := "ops." + spec.Field
+		defs[opsRef] = operatorDocumentSchema(spec, "#/$defs/"+jsonPointerEscape(opsRef))
+
+		variants := []any{map[string]any{"$ref": "#/$defs/" + jsonPointerEscape(opsRef)}}
+		if bare := bareValueSchema(spec); bare != nil {
+			variants = append([]any{bare}, variants...)
+		}
+		props[spec.Field] = map[string]any{
+			"description": spec.Description,
+			"anyOf":       variants,
+		}
+	}
+
+	defs["document"] = map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+
+	schema := map[string]any{
+		"$schema":          "https://json-schema.org/draft/2020-12/schema",
+		"title":            "Filter document",
+		"x-schema-version": FilterDocumentSchemaVersion,
+		"$ref":             "#/$defs/document",
+		"$defs":            defs,
+	}
+	if id != "" {
+		schema["$id"] = id
+	}
+	return schema
+}
+
+// bareValueSchema is the schema of "field": value, or nil when neither eq
+// nor null is allowed. A bare null means null.
+func bareValueSchema(spec fieldSpec) map[string]any {
+	var variants []any
+	if slices.Contains(spec.Operators, Equals) {
+		variants = append(variants, typeSchema(spec.Type, spec.Enum))
+	}
+	if slices.Contains(spec.Operators, IsNull) {
+		variants = append(variants, map[string]any{"type": "null"})
+	}
+	switch len(variants) {
+	case 0:
+		return nil
+	case 1:
+		return variants[0].(map[string]any)
+	default:
+		return map[string]any{"anyOf": variants}
+	}
+}
+
+// operatorDocumentSchema is the schema of {"$gt": 1, "$lt": 5, ...} for
+// spec's field. self is the schema's own reference, for $not.
+func operatorDocumentSchema(spec fieldSpec, self string) map[string]any {
+	has := func(op Clause) bool { return slices.Contains(spec.Operators, op) }
+	props := map[string]any{}
+	var dependent map[string]any
+	for _, op := range spec.Operators {
+		name, ok := mongoOperators[op]
+		if !ok {
+			continue
+		}
+		value := typeSchema(spec.Type, nil)
+		switch op {
+		case Equals, NotEquals:
+			value = typeSchema(spec.Type, spec.Enum)
+		case In, NotIn:
+			value = map[string]any{"type": "array", "minItems": 1, "items": typeSchema(spec.Type, spec.Enum)}
+		}
+		props[name] = value
+	}
+
+	// $eq/$ne null and $exists map onto null and not-null.
+	null, notNull := has(IsNull), has(IsNotNull)
+	switch {
+	case null && notNull:
+		props["$exists"] = map[string]any{"type": "boolean"}
+	case null:
+		props["$exists"] = map[string]any{"const": false}
+	case notNull:
+		props["$exists"] = map[string]any{"const": true}
+	}
+	if null && has(Equals) {
+		props["$eq"] = map[string]any{"anyOf": []any{props["$eq"], map[string]any{"type": "null"}}}
+	} else if null {
+		props["$eq"] = map[string]any{"type": "null"}
+	}
+	if notNull && has(NotEquals) {
+		props["$ne"] = map[string]any{"anyOf": []any{props["$ne"], map[string]any{"type": "null"}}}
+	} else if notNull {
+		props["$ne"] = map[string]any{"type": "null"}
+	}
+
+	// $regex covers the LIKE family: "abc" like, "^abc" starts-with,
+	// "abc$" ends-with. These ignore case, so ParseMongoFilter requires
+	// $options "i" with them; "^abc$" is eq and is left to $eq here.
+	var forms, anchors []string
+	for _, c := range []struct {
+		op      Clause
+		form    string
+		pattern string
+	}{
+		{Contains, `"abc"`, regexLiteralPattern},
+		{StartsWith, `"^abc"`, `\^` + regexLiteralPattern},
+		{EndsWith, `"abc$"`, regexLiteralPattern + `\$`},
+	} {
+		if has(c.op) {
+			anchors = append(anchors, c.form)
+			forms = append(forms, c.pattern)
+		}
+	}
+	if len(anchors) > 0 {
+		props["$regex"] = map[string]any{
+			"type":        "string",
+			"pattern":     "^(?:" + strings.Join(forms, "|") + ")$",
+			"description": "Literal pattern with metacharacters escaped, matched case-insensitively; requires $options \"i\": " + strings.Join(anchors, ", "),
+		}
+		props["$options"] = map[string]any{"const": "i"}
+		dependent = map[string]any{"$regex": []string{"$options"}, "$options": []string{"$regex"}}
+	}
+
+	props["$not"] = map[string]any{"$ref": self}
+	out := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+		"minProperties":        1,
+	}
+	if dependent != nil {
+		out["dependentRequired"] = dependent
+	}
+	return out
+}
+
+// regexLiteralPattern matches the unanchored literal $regex patterns
+// ParseMongoFilter accepts: plain characters and escaped metacharacters.
+const regexLiteralPattern = `(?:[^.*+?()[\]{}|^$\\]|\\[.*+?()[\]{}|^$\\])*`
+
+// jsonPointerEscape escapes a JSON Pointer reference token (RFC 6901).
+func jsonPointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
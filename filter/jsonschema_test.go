@@ -0,0 +1,111 @@
+package filter
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_FilterDocumentSchema(t *testing.T) {
+	db := mustDB(t)
+
+	s := NewFromValues(nil, db.Model(&opUser{})).
+		AllowConfigs(
+			AllowedFilter("age", GreaterThan, LessThanOrEq, In),
+			AllowedFilter("name", Equals, StartsWith).WithEnum("alice", "bob"),
+			AllowedFilter("email", IsNull, IsNotNull),
+		).
+		FilterDocumentSchema("https://example.com/schemas/users-filter.json")
+
+	_, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", s["$schema"])
+	assert.Equal(t, "https://example.com/schemas/users-filter.json", s["$id"])
+	assert.Equal(t, FilterDocumentSchemaVersion, s["x-schema-version"])
+
+	defs := s["$defs"].(map[string]any)
+	doc := defs["document"].(map[string]any)
+	assert.Equal(t, false, doc["additionalProperties"])
+	props := doc["properties"].(map[string]any)
+	for _, key := range []string{"age", "name", "email", "$and", "$or", "$nor", "$not"} {
+		assert.Contains(t, props, key)
+	}
+	assert.EqualValues(t, 1, props["$not"].(map[string]any)["minProperties"])
+	assert.Equal(t, "Filter by age", props["age"].(map[string]any)["description"])
+
+	// age: no eq, so only the operator document
+	assert.Len(t, props["age"].(map[string]any)["anyOf"], 1)
+	ageOps := defs["ops.age"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer"}, ageOps["$gt"])
+	assert.Equal(t, "array", ageOps["$in"].(map[string]any)["type"])
+	assert.NotContains(t, ageOps, "$eq")
+	assert.NotContains(t, ageOps, "$regex")
+
+	// name: bare enum value or {"$eq"/"$regex"}
+	bare := props["name"].(map[string]any)["anyOf"].([]any)[0].(map[string]any)
+	assert.Equal(t, []string{"alice", "bob"}, bare["enum"])
+	nameOps := defs["ops.name"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, nameOps, "$regex")
+	assert.Contains(t, nameOps, "$eq")
+	assert.NotContains(t, nameOps, "$gt")
+
+	// email: null and $exists only
+	emailOps := defs["ops.email"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "boolean"}, emailOps["$exists"])
+	assert.Equal(t, map[string]any{"type": "null"}, emailOps["$eq"])
+}
+
+// Operator documents the published schema accepts must parse, and the other
+// way round. The schema's $regex rules are checked by hand: its pattern,
+// the $options constant and dependentRequired.
+func TestFilterDocumentSchema_RegexAgreesWithParser(t *testing.T) {
+	db := mustDB(t)
+
+	s := NewFromValues(nil, db.Model(&opUser{})).
+		AllowConfigs(AllowedFilter("name", Contains, StartsWith, EndsWith)).
+		FilterDocumentSchema("")
+	ops := s["$defs"].(map[string]any)["ops.name"].(map[string]any)
+	props := ops["properties"].(map[string]any)
+	pattern := regexp.MustCompile(props["$regex"].(map[string]any)["pattern"].(string))
+	dependent := ops["dependentRequired"].(map[string]any)
+
+	schemaAccepts := func(doc map[string]any) bool {
+		for key, required := range dependent {
+			if _, ok := doc[key]; !ok {
+				continue
+			}
+			for _, other := range required.([]string) {
+				if _, ok := doc[other]; !ok {
+					return false
+				}
+			}
+		}
+		if opts, ok := doc["$options"]; ok && opts != props["$options"].(map[string]any)["const"] {
+			return false
+		}
+		re, ok := doc["$regex"].(string)
+		return !ok || pattern.MatchString(re)
+	}
+
+	for _, in := range []string{
+		`{"$regex":"al","$options":"i"}`,
+		`{"$regex":"^al","$options":"i"}`,
+		`{"$regex":"al$","$options":"i"}`,
+		`{"$regex":"a\\.b","$options":"i"}`,
+		`{"$regex":"a\\\\$","$options":"i"}`,
+		`{"$regex":"^al$","$options":"i"}`,
+		`{"$regex":"^al"}`,
+		`{"$regex":"a.*b","$options":"i"}`,
+		`{"$regex":"^\\d+$","$options":"i"}`,
+		`{"$regex":"\\w","$options":"i"}`,
+		`{"$options":"i"}`,
+	} {
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal([]byte(in), &doc), in)
+		_, errs := ParseMongoFilter([]byte(`{"name":` + in + `}`))
+		assert.Equal(t, errs.OK(), schemaAccepts(doc), in)
+	}
+}
@@ -0,0 +1,154 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits bounds how expensive a single request may be. Zero values mean
+// no limit. Requests over a limit fail with CodeFilterComplexity before any
+// query is run.
+type Limits struct {
+	// OperatorCosts overrides DefaultOperatorCosts per operator.
+	OperatorCosts map[Clause]int
+	// MaxFilters caps the number of filters, counting nested groups.
+	MaxFilters int
+	// MaxListSize caps the number of values of an In or NotIn filter.
+	MaxListSize int
+	// MaxSortKeys caps the number of sort fields.
+	MaxSortKeys int
+	// MaxGroupDepth caps how deeply OR and NOT groups may nest.
+	MaxGroupDepth int
+	// MaxPatternLength caps the length of like, starts-with and ends-with values.
+	MaxPatternLength int
+	// MaxCost caps the summed cost of all filters and sort keys. A filter
+	// costs its operator cost times the field's FilterConfig.Cost (default 1);
+	// each sort key costs 1.
+	MaxCost int
+	// RequireIndexForWildcard rejects leading-wildcard patterns (like,
+	// not-like, ends-with) on fields whose FilterConfig is not Indexed.
+	RequireIndexForWildcard bool
+}
+
+// DefaultOperatorCosts weighs operators by how hard they are on an index.
+// In and NotIn additionally cost one per ten values.
+var DefaultOperatorCosts = map[Clause]int{
+	Equals:          1,
+	NotEquals:       1,
+	GreaterThan:     1,
+	GreaterThanOrEq: 1,
+	LessThan:        1,
+	LessThanOrEq:    1,
+	IsNull:          1,
+	IsNotNull:       1,
+	Between:         1,
+	NotBetween:      2,
+	In:              1,
+	NotIn:           2,
+	StartsWith:      2,
+	Contains:        5,
+	NotContains:     5,
+	EndsWith:        5,
+}
+
+// WithLimits sets the complexity limits checked by Apply.
+func (b *Builder) WithLimits(limits Limits) *Builder {
+	b.limits = &limits
+	return b
+}
+
+// checkLimits enforces b.limits on the client expression and sort.
+func (b *Builder) checkLimits(expr Group, sortParam string) {
+	l := b.limits
+	if l == nil {
+		return
+	}
+
+	filters := expr.AllFilters()
+	if l.MaxFilters > 0 && len(filters) > l.MaxFilters {
+		b.result.AddError(NewComplexityError(ReasonTooManyFilters, "", "",
+			fmt.Sprintf("Too many filters: %d, at most %d allowed", len(filters), l.MaxFilters)))
+	}
+	if depth := groupDepth(expr); l.MaxGroupDepth > 0 && depth > l.MaxGroupDepth {
+		b.result.AddError(NewComplexityError(ReasonGroupTooDeep, "", "",
+			fmt.Sprintf("Logical groups nest %d deep, at most %d allowed", depth, l.MaxGroupDepth)))
+	}
+
+	sortKeys := 0
+	for _, s := range strings.Split(sortParam, ",") {
+		if strings.TrimSpace(s) != "" {
+			sortKeys++
+		}
+	}
+	if l.MaxSortKeys > 0 && sortKeys > l.MaxSortKeys {
+		b.result.AddError(NewComplexityError(ReasonTooManySortKeys, "", "",
+			fmt.Sprintf("Too many sort fields: %d, at most %d allowed", sortKeys, l.MaxSortKeys)).at(b.sortParam()))
+	}
+
+	cost := sortKeys
+	for _, f := range filters {
+		value := fmt.Sprint(f.Value)
+		listSize := 0
+		switch f.Operator {
+		case In, NotIn:
+			listSize = len(parseCommaSeparatedValues(value))
+			if l.MaxListSize > 0 && listSize > l.MaxListSize {
+				b.result.AddError(NewComplexityError(ReasonListTooLong, f.Field, string(f.Operator),
+					fmt.Sprintf("Too many values: %d, at most %d allowed", listSize, l.MaxListSize)).at(f.Parameter))
+			}
+		case Contains, NotContains, StartsWith, EndsWith:
+			if n := utf8.RuneCountInString(value); l.MaxPatternLength > 0 && n > l.MaxPatternLength {
+				b.result.AddError(NewComplexityError(ReasonPatternTooLong, f.Field, string(f.Operator),
+					fmt.Sprintf("Pattern is %d characters, at most %d allowed", n, l.MaxPatternLength)).at(f.Parameter))
+			}
+			if f.Operator != StartsWith && l.RequireIndexForWildcard && !b.fieldConfig(f.Field).Indexed {
+				b.result.AddError(NewComplexityError(ReasonWildcardNotIndexed, f.Field, string(f.Operator),
+					fmt.Sprintf("Operator '%s' needs an index on '%s'; use starts-with", f.Operator, f.Field)).at(f.Parameter))
+			}
+		}
+		cost += l.filterCost(f, b.fieldConfig(f.Field).Cost, listSize)
+	}
+	if l.MaxCost > 0 && cost > l.MaxCost {
+		b.result.AddError(NewComplexityError(ReasonCostExceeded, "", "",
+			fmt.Sprintf("Query cost %d exceeds the budget of %d", cost, l.MaxCost)))
+	}
+}
+
+// filterCost is the operator cost times the field weight.
+func (l *Limits) filterCost(f Filter, fieldCost, listSize int) int {
+	opCost, ok := l.OperatorCosts[f.Operator]
+	if !ok {
+		opCost, ok = DefaultOperatorCosts[f.Operator]
+	}
+	if !ok {
+		opCost = 1
+	}
+	opCost += listSize / 10
+	if fieldCost <= 0 {
+		fieldCost = 1
+	}
+	return opCost * fieldCost
+}
+
+// fieldConfig returns the FilterConfig of field, or the zero config.
+func (b *Builder) fieldConfig(field string) FilterConfig {
+	for _, c := range b.configs {
+		if c.Field == field {
+			return c
+		}
+	}
+	return FilterConfig{}
+}
+
+// groupDepth counts the nesting of OR and NOT groups in g.
+func groupDepth(g Group) int {
+	deepest := 0
+	for _, sub := range g.Groups {
+		deepest = max(deepest, groupDepth(sub))
+	}
+	if g.Logic == Or || g.Not {
+		return deepest + 1
+	}
+	return deepest
+}
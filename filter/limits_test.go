@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Limits(t *testing.T) {
+	db := mustDB(t)
+
+	limits := Limits{
+		MaxFilters:              3,
+		MaxListSize:             3,
+		MaxSortKeys:             1,
+		MaxGroupDepth:           1,
+		MaxPatternLength:        5,
+		MaxCost:                 12,
+		RequireIndexForWildcard: true,
+	}
+	configs := []FilterConfig{
+		AllowedFilter("name", Equals, Contains, StartsWith, In),
+		AllowedFilter("email", Equals, Contains),
+		AllowedFilter("age", GreaterThan, LessThan),
+	}
+	configs[1].Indexed = true
+	configs[2].Cost = 4
+
+	cases := map[string]struct {
+		q      url.Values
+		reason ErrorReason
+	}{
+		"too many filters":   {url.Values{"filter": {"name==a;name==b;name==c;name==d"}}, ReasonTooManyFilters},
+		"list too long":      {url.Values{"filter[name][in]": {"a,b,c,d"}}, ReasonListTooLong},
+		"too many sort keys": {url.Values{"sort": {"name,age"}}, ReasonTooManySortKeys},
+		"groups too deep":    {url.Values{"filter": {"name==a,(name==b;(name==c,name==d))"}}, ReasonGroupTooDeep},
+		"pattern too long":   {url.Values{"filter[name][starts-with]": {"abcdef"}}, ReasonPatternTooLong},
+		"unindexed wildcard": {url.Values{"filter[name][like]": {"al"}}, ReasonWildcardNotIndexed},
+		"over budget":        {url.Values{"filter[email][like]": {"a"}, "filter[age][gt]": {"1"}, "filter[age][lt]": {"99"}}, ReasonCostExceeded},
+	}
+	for name, tc := range cases {
+		b := NewFromValues(tc.q, db.Model(&opUser{})).
+			AllowConfigs(configs...).
+			AllowSorts("name", "age").
+			WithRSQL("").
+			WithLimits(limits).
+			Apply()
+		require.False(t, b.OK(), name)
+		assert.Equal(t, CodeFilterComplexity, b.GetErrors().First().Code, name)
+		assert.True(t, b.GetErrors().AnyIs(tc.reason), name)
+	}
+
+	b := NewFromValues(url.Values{"filter[email][like]": {"a"}, "filter[age][gt]": {"1"}, "sort": {"age"}}, db.Model(&opUser{})).
+		AllowConfigs(configs...).
+		AllowSorts("name", "age").
+		WithLimits(limits).
+		Apply()
+	assert.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+}
@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Schema configures the Builder of one endpoint: allowlists, configs, sorts,
+// error format and so on. HTTPMiddleware (and ginfilter.Middleware) run it on
+// every request and call Apply themselves, so a Schema must not.
+//
+//	users := func(b *filter.Builder) *filter.Builder {
+//		return b.AllowConfigs(configs...).AllowSorts("name").WithErrorFormat(filter.FormatProblem)
+//	}
+type Schema func(b *Builder) *Builder
+
+type builderKey struct{}
+
+// NewContext returns a copy of ctx carrying b.
+func NewContext(ctx context.Context, b *Builder) context.Context {
+	return context.WithValue(ctx, builderKey{}, b)
+}
+
+// FromContext returns the Builder stored by HTTPMiddleware or
+// ginfilter.Middleware, or nil. With Gin, pass c.Request.Context().
+func FromContext(ctx context.Context) *Builder {
+	b, _ := ctx.Value(builderKey{}).(*Builder)
+	return b
+}
+
+// QueryFromContext returns the filtered query stored by HTTPMiddleware or
+// ginfilter.Middleware, or nil.
+func QueryFromContext(ctx context.Context) *gorm.DB {
+	if b := FromContext(ctx); b != nil {
+		return b.Query()
+	}
+	return nil
+}
+
+// HTTPMiddleware applies schema to query for every request. When the
+// request is invalid it responds with FilterErrors.Status() and the body of
+// Builder.ErrorResponse; otherwise the Builder is stored in the request
+// context for the next handler:
+//
+//	mux.Handle("/users", filter.HTTPMiddleware(db.Model(&User{}), users)(listUsers))
+//
+//	func listUsers(w http.ResponseWriter, r *http.Request) {
+//		var list []User
+//		filter.QueryFromContext(r.Context()).Find(&list)
+//		...
+//	}
+//
+// query is shared between requests; each request runs on its own session
+// bound to the request context. For Gin, see ginfilter.Middleware.
+func HTTPMiddleware(query *gorm.DB, schema Schema) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b := schema(NewFromRequest(r, query.WithContext(r.Context()))).Apply()
+			if !b.OK() {
+				status, contentType, body := b.ErrorResponse(b.GetErrors())
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(status)
+				_ = json.NewEncoder(w).Encode(body)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), b)))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestWithQuery(q url.Values) *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
+}
+
+func usersSchema(b *Builder) *Builder {
+	return b.
+		AllowConfigs(
+			AllowedFilter("name", StartsWith),
+			AllowedFilter("age", GreaterThan),
+		).
+		AllowSorts("name").
+		WithErrorFormat(FormatProblem)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	db := mustDB(t)
+
+	called := false
+	h := HTTPMiddleware(db.Model(&opUser{}), usersSchema)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		var users []opUser
+		require.NoError(t, QueryFromContext(r.Context()).Find(&users).Error)
+		assert.Len(t, users, 2)
+		assert.NotNil(t, FromContext(r.Context()))
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?filter[age][gt]=20", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, called)
+
+	called = false
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?sort=email", nil))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+}
+
+func TestHTTPMiddleware_Request(t *testing.T) {
+	db := mustDB(t)
+	catalog := NewCatalog()
+	require.NoError(t, catalog.Add("fr", map[string]string{"FIELD_NOT_ALLOWED": "Champ « {{.Field}} » interdit"}))
+	schema := func(b *Builder) *Builder {
+		return usersSchema(b).Paginate(2, 10).WithTranslator(catalog)
+	}
+
+	var links PageLinks
+	h := HTTPMiddleware(db.Model(&opUser{}), schema)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, errs := FromContext(r.Context()).Pagination()
+		require.Nil(t, errs)
+		links = p.Links
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?sort=name", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/users?page%5Bnumber%5D=2&page%5Bsize%5D=2&sort=name", links.Next)
+
+	req := httptest.NewRequest(http.MethodGet, "/users?filter[email]=x", nil)
+	req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var problem Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "/users?filter[email]=x", problem.Instance)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "Champ « email » interdit", problem.Errors[0].Detail)
+}
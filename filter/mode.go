@@ -0,0 +1,47 @@
+package filter
+
+// Mode decides what happens to a filter, sort or other query parameter that
+// is unknown or invalid.
+type Mode string
+
+const (
+	// ModeStrict fails the request (the default).
+	ModeStrict Mode = "strict"
+	// ModeLenient silently drops the offending item and applies the rest.
+	ModeLenient Mode = "lenient"
+	// ModeWarn drops the offending item like ModeLenient and records it in
+	// Result.Warnings so the response can tell the client.
+	ModeWarn Mode = "warn"
+)
+
+// WithMode sets how invalid client input is handled. Only validation and
+// parsing errors of individual items are relaxed; permission, scope,
+// required-filter and complexity errors always fail the request, as do
+// server-side errors. A required filter that is dropped is missing and
+// fails the request. Dropping a term of an OR or NOT group keeps the rest
+// of the group, which still changes what the group matches.
+func (b *Builder) WithMode(mode Mode) *Builder {
+	b.mode = mode
+	return b
+}
+
+// Warnings returns the problems dropped in ModeWarn.
+func (b *Builder) Warnings() []*FilterError {
+	if b.result == nil {
+		return nil
+	}
+	return b.result.Warnings
+}
+
+// reject records client input errors according to the builder's mode.
+func (b *Builder) reject(errs ...*FilterError) {
+	for _, err := range errs {
+		relaxed := err.Type == ErrorTypeValidation || err.Type == ErrorTypeParsing
+		switch {
+		case !relaxed || b.mode == "" || b.mode == ModeStrict:
+			b.result.AddError(err)
+		case b.mode == ModeWarn:
+			b.result.AddWarning(err)
+		}
+	}
+}
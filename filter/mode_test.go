@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_Modes(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[name][starts-with]", "al")
+	q.Set("filter[password]", "x")
+	q.Set("filter[age][bogus]", "1")
+	q.Set("sort", "-age,secret")
+
+	build := func(mode Mode) *Builder {
+		return NewFromValues(q, db.Model(&opUser{})).
+			AllowFields("name", "age").
+			WithMode(mode).
+			Apply()
+	}
+
+	strict := build(ModeStrict)
+	assert.False(t, strict.OK())
+	assert.Equal(t, 3, strict.GetErrors().Len())
+
+	for _, mode := range []Mode{ModeLenient, ModeWarn} {
+		b := build(mode)
+		require.True(t, b.OK(), "%s: unexpected errors: %+v", mode, b.GetErrors())
+
+		var got []opUser
+		require.NoError(t, b.Query().Find(&got).Error)
+		require.Len(t, got, 3, mode)
+		assert.Equal(t, "ALF", got[0].Name, mode)
+	}
+
+	assert.Empty(t, build(ModeLenient).Warnings())
+	warned := build(ModeWarn)
+	assert.Len(t, warned.Warnings(), 3)
+	assert.Contains(t, warned.Result().ToJSONResponse(), "warnings")
+}
+
+func TestBuilder_Modes_PermissionStillFails(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(url.Values{"filter[email]": {"a@x"}}, db.Model(&opUser{})).
+		AllowConfigs(AllowedFilter("email").RequirePermissions("admin")).
+		WithMode(ModeLenient).
+		Apply()
+	assert.False(t, b.OK())
+}
+
+func TestBuilder_Modes_RequiredFilterDropped(t *testing.T) {
+	db := mustDB(t)
+
+	for _, mode := range []Mode{ModeStrict, ModeLenient, ModeWarn} {
+		b := NewFromValues(url.Values{"filter[name][eq]": {""}}, db.Model(&opUser{})).
+			AllowConfigs(AllowedFilter("name").AsRequired()).
+			WithMode(mode).
+			Apply()
+		require.False(t, b.OK(), mode)
+		assert.True(t, b.GetErrors().AnyIs(ReasonFilterRequired), mode)
+	}
+}
+
+func TestBuilder_Modes_DroppedFilterKeepsDefault(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(url.Values{"filter[age][ne]": {""}}, db.Model(&opUser{})).
+		AllowConfigs(AllowedFilter("age", Equals, NotEquals).WithDefault("20")).
+		WithMode(ModeLenient).
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 1)
+	assert.Equal(t, "alice", got[0].Name)
+}
+
+func TestBuilder_Modes_DropsOnlyInvalidTerm(t *testing.T) {
+	db := mustDB(t)
+
+	cases := map[string]struct {
+		build func(b *Builder) *Builder
+		want  int
+	}{
+		"or": {
+			build: func(b *Builder) *Builder { return b.WithRSQL("") },
+			want:  1,
+		},
+		"not": {
+			build: func(b *Builder) *Builder { return b.WithMongo([]byte(`{"$nor": [{"name": "bob"}, {"secret": "x"}]}`)) },
+			want:  4,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := url.Values{"filter": {"name==bob,secret==x"}}
+			b := tc.build(NewFromValues(q, db.Model(&opUser{})).AllowFields("name").WithMode(ModeWarn)).Apply()
+			require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+			assert.Len(t, b.Warnings(), 1)
+
+			var got []opUser
+			require.NoError(t, b.Query().Find(&got).Error)
+			assert.Len(t, got, tc.want)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ParseMongoFilter parses a MongoDB-style query document into a Group, e.g.
+//
+//	{"price":{"$gt":10},"$or":[{"status":"a"},{"tag":{"$in":["x","y"]}}]}
+//
+// Supported operators:
+//
+//	field: value          eq (null -> null)
+//	$eq $ne $gt $gte $lt $lte
+//	$in $nin              in / not-in
+//	$exists               not-null / null for true / false
+//	$regex                literal patterns only: "abc" like, "^abc" starts-with
+//	                      and "abc$" ends-with with $options "i" (the LIKE
+//	                      clauses ignore case), "^abc$" eq without it;
+//	                      metacharacters must be escaped ("a\.b"), other
+//	                      escapes such as \d are rejected
+//	$not                  negates a field's operator document, or a whole
+//	                      document at the top level
+//	$and $or $nor         logical groups over arrays of non-empty documents
+//
+// The result contains no raw SQL: every condition becomes a Filter, so the
+// Validator allowlists apply when it is run through the Applier. Unknown
+// operators, unsupported value shapes and data after the document are
+// reported as parsing errors. An empty document matches everything.
+func ParseMongoFilter(doc []byte) (Group, *FilterErrors) {
+	errs := &FilterErrors{}
+
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var root map[string]any
+	if err := dec.Decode(&root); err != nil {
+		errs.Add(NewParsingError("", "", fmt.Sprintf("Invalid filter document: %v", err), err))
+		return Group{}, errs
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		errs.Add(NewParsingError("", "", "Invalid filter document: unexpected data after the document", nil))
+		return Group{}, errs
+	}
+
+	g := mongoDocument(root, errs)
+	return g, errs
+}
+
+// mongoDocument converts one query document (implicitly ANDed keys).
+func mongoDocument(doc map[string]any, errs *FilterErrors) Group {
+	terms := make([]Group, 0, len(doc))
+	for _, key := range sortedKeys(doc) {
+		val := doc[key]
+		switch key {
+		case "$and", "$or", "$nor":
+			if g, ok := mongoLogical(key, val, errs); ok {
+				terms = append(terms, g)
+			}
+		case "$not":
+			sub, ok := val.(map[string]any)
+			if !ok || len(sub) == 0 {
+				errs.Add(NewParsingError("", fmt.Sprint(val), "$not expects a non-empty document", nil))
+				continue
+			}
+			terms = append(terms, negateGroup(mongoDocument(sub, errs)))
+		default:
+			if strings.HasPrefix(key, "$") {
+				errs.Add(NewParsingError("", key, fmt.Sprintf("Unsupported operator '%s'", key), nil))
+				continue
+			}
+			if g, ok := mongoField(key, val, errs); ok {
+				terms = append(terms, g)
+			}
+		}
+	}
+	if len(terms) == 0 {
+		return Group{Logic: And}
+	}
+	return joinGroups(And, terms)
+}
+
+func mongoLogical(op string, val any, errs *FilterErrors) (Group, bool) {
+	items, ok := val.([]any)
+	if !ok || len(items) == 0 {
+		errs.Add(NewParsingError("", fmt.Sprint(val), fmt.Sprintf("%s expects a non-empty array of documents", op), nil))
+		return Group{}, false
+	}
+	terms := make([]Group, 0, len(items))
+	for _, item := range items {
+		// An empty document matches everything, which a Group cannot say
+		// inside $or or $nor; joinGroups would drop it instead.
+		sub, ok := item.(map[string]any)
+		if !ok || len(sub) == 0 {
+			errs.Add(NewParsingError("", fmt.Sprint(item), fmt.Sprintf("%s expects a non-empty array of documents", op), nil))
+			return Group{}, false
+		}
+		terms = append(terms, mongoDocument(sub, errs))
+	}
+	switch op {
+	case "$and":
+		return joinGroups(And, terms), true
+	case "$or":
+		return joinGroups(Or, terms), true
+	default: // $nor
+		return negateGroup(Group{Logic: Or, Groups: terms}), true
+	}
+}
+
+// mongoField converts "field: value" or "field: {operator document}".
+func mongoField(field string, val any, errs *FilterErrors) (Group, bool) {
+	ops, isDoc := val.(map[string]any)
+	if !isDoc || !hasOperatorKeys(ops) {
+		f, ok := mongoCondition(field, "$eq", val, nil, errs)
+		if !ok {
+			return Group{}, false
+		}
+		return Group{Logic: And, Filters: []Filter{f}}, true
+	}
+
+	terms := make([]Group, 0, len(ops))
+	for _, op := range sortedKeys(ops) {
+		arg := ops[op]
+		switch op {
+		case "$options":
+			// consumed by $regex
+			if _, ok := ops["$regex"]; !ok {
+				errs.Add(NewParsingError(field, fmt.Sprint(arg), "$options requires $regex", nil))
+			}
+			continue
+		case "$not":
+			sub, ok := arg.(map[string]any)
+			if !ok {
+				errs.Add(NewParsingError(field, fmt.Sprint(arg), "$not expects an operator document", nil))
+				continue
+			}
+			if g, ok := mongoField(field, sub, errs); ok {
+				terms = append(terms, negateGroup(g))
+			}
+		default:
+			if f, ok := mongoCondition(field, op, arg, ops, errs); ok {
+				terms = append(terms, Group{Logic: And, Filters: []Filter{f}})
+			}
+		}
+	}
+	if len(terms) == 0 {
+		return Group{}, false
+	}
+	return joinGroups(And, terms), true
+}
+
+func mongoCondition(field, op string, arg any, siblings map[string]any, errs *FilterErrors) (Filter, bool) {
+	switch op {
+	case "$eq", "$ne":
+		if arg == nil {
+			if op == "$eq" {
+				return Filter{Field: field, Operator: IsNull, Value: ""}, true
+			}
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, true
+		}
+		v, ok := mongoScalar(field, op, arg, errs)
+		clause := Equals
+		if op == "$ne" {
+			clause = NotEquals
+		}
+		return Filter{Field: field, Operator: clause, Value: v}, ok
+
+	case "$gt", "$gte", "$lt", "$lte":
+		v, ok := mongoScalar(field, op, arg, errs)
+		clauses := map[string]Clause{"$gt": GreaterThan, "$gte": GreaterThanOrEq, "$lt": LessThan, "$lte": LessThanOrEq}
+		return Filter{Field: field, Operator: clauses[op], Value: v}, ok
+
+	case "$in", "$nin":
+		items, ok := arg.([]any)
+		if !ok || len(items) == 0 {
+			errs.Add(NewParsingError(field, fmt.Sprint(arg), fmt.Sprintf("%s expects a non-empty array", op), nil))
+			return Filter{}, false
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			v, ok := mongoScalar(field, op, item, errs)
+			if !ok {
+				return Filter{}, false
+			}
+			if strings.Contains(v, ",") {
+				errs.Add(NewParsingError(field, v, fmt.Sprintf("%s values cannot contain ','", op), nil))
+				return Filter{}, false
+			}
+			values = append(values, v)
+		}
+		clause := In
+		if op == "$nin" {
+			clause = NotIn
+		}
+		return Filter{Field: field, Operator: clause, Value: strings.Join(values, ",")}, true
+
+	case "$exists":
+		exists, ok := arg.(bool)
+		if !ok {
+			errs.Add(NewParsingError(field, fmt.Sprint(arg), "$exists expects true or false", nil))
+			return Filter{}, false
+		}
+		if exists {
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, true
+		}
+		return Filter{Field: field, Operator: IsNull, Value: ""}, true
+
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			errs.Add(NewParsingError(field, fmt.Sprint(arg), "$regex expects a string pattern", nil))
+			return Filter{}, false
+		}
+		var fold bool
+		switch opts := siblings["$options"]; opts {
+		case nil, "":
+		case "i":
+			fold = true
+		default:
+			errs.Add(NewParsingError(field, fmt.Sprint(opts), "Only the 'i' $options flag is supported", nil))
+			return Filter{}, false
+		}
+		clause, literal, ok := regexClause(pattern)
+		if !ok {
+			errs.Add(NewParsingError(field, pattern, "Only literal $regex patterns with optional ^ and $ anchors are supported", nil))
+			return Filter{}, false
+		}
+		// The LIKE clauses ignore case and eq does not, so only patterns
+		// whose case rule matches $options can be kept.
+		switch {
+		case clause == Equals && fold:
+			errs.Add(NewParsingError(field, pattern, "Anchored '^...$' $regex patterns are case-sensitive and cannot take $options 'i'", nil))
+			return Filter{}, false
+		case clause != Equals && !fold:
+			errs.Add(NewParsingError(field, pattern, "$regex patterns other than '^...$' are case-insensitive and need $options 'i'", nil))
+			return Filter{}, false
+		}
+		return Filter{Field: field, Operator: clause, Value: literal}, true
+
+	default:
+		errs.Add(NewParsingError(field, op, fmt.Sprintf("Unsupported operator '%s'", op), nil))
+		return Filter{}, false
+	}
+}
+
+// mongoScalar renders a JSON scalar as a filter value.
+func mongoScalar(field, op string, v any, errs *FilterErrors) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return fmt.Sprint(t), true
+	default:
+		errs.Add(NewParsingError(field, fmt.Sprint(v), fmt.Sprintf("%s expects a string, number or boolean", op), nil))
+		return "", false
+	}
+}
+
+// regexMeta lists the regex metacharacters; escaped, they match themselves.
+const regexMeta = `.*+?()[]{}|^$\`
+
+// regexClause maps an anchored literal pattern onto a LIKE-style clause.
+func regexClause(pattern string) (Clause, string, bool) {
+	literal := pattern
+	start := strings.HasPrefix(literal, "^")
+	if start {
+		literal = literal[1:]
+	}
+	// A final "$" is an anchor unless an odd number of backslashes
+	// escapes it.
+	trimmed := strings.TrimSuffix(literal, "$")
+	end := len(trimmed) < len(literal) &&
+		(len(trimmed)-len(strings.TrimRight(trimmed, `\`)))%2 == 0
+	if end {
+		literal = literal[:len(literal)-1]
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(literal); i++ {
+		c := literal[i]
+		if c == '\\' && i+1 < len(literal) && strings.IndexByte(regexMeta, literal[i+1]) >= 0 {
+			i++
+			sb.WriteByte(literal[i])
+			continue
+		}
+		// Other escapes are classes or assertions (\d, \w, \b, ...).
+		if strings.IndexByte(regexMeta, c) >= 0 {
+			return "", "", false
+		}
+		sb.WriteByte(c)
+	}
+
+	switch {
+	case start && end:
+		return Equals, sb.String(), true
+	case start:
+		return StartsWith, sb.String(), true
+	case end:
+		return EndsWith, sb.String(), true
+	default:
+		return Contains, sb.String(), true
+	}
+}
+
+func hasOperatorKeys(doc map[string]any) bool {
+	for k := range doc {
+		if strings.HasPrefix(k, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
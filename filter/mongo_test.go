@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMongoFilter(t *testing.T) {
+	g, errs := ParseMongoFilter([]byte(`{"price":{"$gt":10},"$or":[{"status":"a"},{"tag":{"$in":["x","y"]}}]}`))
+	require.True(t, errs.OK(), "unexpected errors: %+v", errs)
+
+	assert.Equal(t, And, g.Logic)
+	assert.Equal(t, []Filter{{Field: "price", Operator: GreaterThan, Value: "10"}}, g.Filters)
+	require.Len(t, g.Groups, 1)
+	assert.Equal(t, Or, g.Groups[0].Logic)
+	assert.Equal(t, []Filter{
+		{Field: "status", Operator: Equals, Value: "a"},
+		{Field: "tag", Operator: In, Value: "x,y"},
+	}, g.Groups[0].Filters)
+}
+
+func TestParseMongoFilter_Operators(t *testing.T) {
+	cases := map[string]Filter{
+		`{"email":null}`:                              {Field: "email", Operator: IsNull, Value: ""},
+		`{"email":{"$exists":true}}`:                  {Field: "email", Operator: IsNotNull, Value: ""},
+		`{"age":{"$lte":20.5}}`:                       {Field: "age", Operator: LessThanOrEq, Value: "20.5"},
+		`{"name":{"$ne":"bob"}}`:                      {Field: "name", Operator: NotEquals, Value: "bob"},
+		`{"name":{"$nin":["a","b"]}}`:                 {Field: "name", Operator: NotIn, Value: "a,b"},
+		`{"name":{"$regex":"^al","$options":"i"}}`:    {Field: "name", Operator: StartsWith, Value: "al"},
+		`{"name":{"$regex":"na$","$options":"i"}}`:    {Field: "name", Operator: EndsWith, Value: "na"},
+		`{"name":{"$regex":"a\\.b","$options":"i"}}`:  {Field: "name", Operator: Contains, Value: "a.b"},
+		`{"name":{"$regex":"^bob$"}}`:                 {Field: "name", Operator: Equals, Value: "bob"},
+		`{"name":{"$regex":"a\\\\$","$options":"i"}}`: {Field: "name", Operator: EndsWith, Value: "a\\"},
+		`{"name":{"$regex":"^1\\+1\\$$"}}`:            {Field: "name", Operator: Equals, Value: "1+1$"},
+		`{"active":true}`:                             {Field: "active", Operator: Equals, Value: "true"},
+	}
+	for in, want := range cases {
+		g, errs := ParseMongoFilter([]byte(in))
+		require.True(t, errs.OK(), "%s: %+v", in, errs)
+		assert.Equal(t, []Filter{want}, g.Filters, in)
+	}
+}
+
+func TestParseMongoFilter_EmptyDocument(t *testing.T) {
+	g, errs := ParseMongoFilter([]byte(` {} `))
+	require.True(t, errs.OK())
+	assert.True(t, g.IsEmpty())
+}
+
+func TestParseMongoFilter_Not(t *testing.T) {
+	g, errs := ParseMongoFilter([]byte(`{"age":{"$not":{"$gt":20}}}`))
+	require.True(t, errs.OK())
+	assert.True(t, g.Not)
+	assert.Equal(t, []Filter{{Field: "age", Operator: GreaterThan, Value: "20"}}, g.Filters)
+}
+
+func TestParseMongoFilter_Errors(t *testing.T) {
+	for _, in := range []string{
+		`{"name":{"$where":"1=1"}}`,
+		`{"$where":"sleep(100)"}`,
+		`{"name":{"$regex":"a.*b"}}`,
+		`{"name":{"$regex":"ab","$options":"m"}}`,
+		`{"name":{"$regex":"^al"}}`,
+		`{"name":{"$regex":"^\\d+$"}}`,
+		`{"name":{"$regex":"\\w","$options":"i"}}`,
+		`{"name":{"$regex":"^bob$","$options":"i"}}`,
+		`{"name":{"$options":"i"}}`,
+		`{"name":{"$eq":"bob","$options":"i"}}`,
+		`{"name":{"$in":"a"}}`,
+		`{"name":{"$in":["a,b"]}}`,
+		`{"name":["a"]}`,
+		`{"$or":{"a":1}}`,
+		`{"$or":[{},{"name":"x"}]}`,
+		`{"$nor":[{}]}`,
+		`{"$not":{}}`,
+		`{"name":"x"} garbage`,
+		`{"name":"x"}{"age":1}`,
+		`[1,2]`,
+	} {
+		_, errs := ParseMongoFilter([]byte(in))
+		assert.False(t, errs.OK(), in)
+	}
+}
+
+func TestBuilder_WithMongo(t *testing.T) {
+	db := mustDB(t)
+
+	res := NewFromValues(nil, db.Model(&opUser{})).
+		AllowFields("name", "age", "email").
+		WithMongo([]byte(`{"age":{"$gte":17},"$or":[{"name":{"$regex":"^al","$options":"i"}},{"email":{"$exists":false}}],"$nor":[{"name":"bob"}]}`)).
+		Apply().
+		Result()
+
+	assert.Equal(t, []string{"ALF", "alice", "alina"}, namesOf(fetch(t, res)))
+}
+
+func TestBuilder_WithMongo_UsesAllowlist(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(nil, db.Model(&opUser{})).
+		AllowFields("name").
+		WithMongo([]byte(`{"$or":[{"name":"alice"},{"email":{"$exists":true}}]}`)).
+		Apply()
+
+	require.False(t, b.OK())
+	assert.Equal(t, "email", b.GetErrors().First().Field)
+}
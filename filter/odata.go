@@ -0,0 +1,374 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ODataQuery holds the supported OData v4 system query options.
+type ODataQuery struct {
+	Top     *int
+	Skip    *int
+	OrderBy string // Applier sort syntax, e.g. "-price,name"
+	Filter  Group
+	Count   bool
+}
+
+// ParseOData parses $filter, $orderby, $top, $skip and $count from query
+// values. Any other OData system query option ($select, $expand, $search,
+// ...) is reported as an error instead of being ignored.
+func ParseOData(values url.Values) (*ODataQuery, *FilterErrors) {
+	q := &ODataQuery{}
+	errs := &FilterErrors{}
+
+	for key, vals := range values {
+		if !strings.HasPrefix(key, "$") || len(vals) == 0 {
+			continue
+		}
+		raw := strings.TrimSpace(vals[0])
+
+		switch key {
+		case "$filter":
+			g, err := ParseODataFilter(raw)
+			if err != nil {
+				errs.Add(err.at(key))
+				continue
+			}
+			q.Filter = withParameter(g, key)
+
+		case "$orderby":
+			spec, err := parseDirectionalSort(raw, "$orderby")
+			if err != nil {
+				errs.Add(err.at(key))
+				continue
+			}
+			q.OrderBy = spec
+
+		case "$top", "$skip":
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				errs.Add(NewParsingError("", raw, fmt.Sprintf("Invalid %s value '%s': expected a non-negative integer", key, raw), err).at(key))
+				continue
+			}
+			if key == "$top" {
+				q.Top = &n
+			} else {
+				q.Skip = &n
+			}
+
+		case "$count":
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				errs.Add(NewParsingError("", raw, fmt.Sprintf("Invalid $count value '%s': expected true or false", raw), err).at(key))
+				continue
+			}
+			q.Count = b
+
+		default:
+			errs.Add(NewParsingError("", raw, fmt.Sprintf("Unsupported OData query option '%s'", key), nil).at(key))
+		}
+	}
+
+	return q, errs
+}
+
+// ParseODataFilter parses an OData v4 $filter expression into a Group.
+//
+// Supported: eq, ne, gt, ge, lt, le and in comparisons against literals,
+// "eq null" / "ne null", the and/or/not logical operators with parentheses,
+// and the contains, startswith and endswith functions (optionally compared
+// with eq/ne true/false). Arithmetic, lambda operators, navigation paths and
+// other functions produce a syntax error at their position.
+func ParseODataFilter(input string) (Group, *FilterError) {
+	p := &odataParser{scanner{input: input}}
+	p.skipSpace()
+	if p.eof() {
+		return Group{}, nil
+	}
+	g, err := p.parseOr()
+	if err != nil {
+		return Group{}, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return Group{}, p.errorAt(p.pos, fmt.Sprintf("unexpected character '%c'", p.peek()))
+	}
+	return g, nil
+}
+
+type odataParser struct {
+	scanner
+}
+
+func (p *odataParser) parseOr() (Group, *FilterError) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for {
+		p.skipSpace()
+		if !p.acceptKeyword("or", true) {
+			break
+		}
+		next, err := p.parseAnd()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(Or, terms), nil
+}
+
+func (p *odataParser) parseAnd() (Group, *FilterError) {
+	first, err := p.parseNot()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for {
+		p.skipSpace()
+		if !p.acceptKeyword("and", true) {
+			break
+		}
+		next, err := p.parseNot()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(And, terms), nil
+}
+
+func (p *odataParser) parseNot() (Group, *FilterError) {
+	p.skipSpace()
+	if !p.acceptKeyword("not", true) {
+		return p.parsePrimary()
+	}
+	g, err := p.parseNot()
+	if err != nil {
+		return Group{}, err
+	}
+	return negateGroup(g), nil
+}
+
+func (p *odataParser) parsePrimary() (Group, *FilterError) {
+	p.skipSpace()
+	if p.eof() {
+		return Group{}, p.errorAt(p.pos, "unexpected end of $filter, expected a comparison")
+	}
+	if p.peek() == '(' {
+		p.pos++
+		g, err := p.parseOr()
+		if err != nil {
+			return Group{}, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return Group{}, p.errorAt(p.pos, "expected ')'")
+		}
+		p.pos++
+		return g, nil
+	}
+
+	identPos := p.pos
+	ident := p.readWhile(isODataIdent)
+	if ident == "" {
+		return Group{}, p.errorAt(identPos, fmt.Sprintf("unexpected character '%c'", p.peek()))
+	}
+	if p.peek() == '(' {
+		return p.parseFunction(ident, identPos)
+	}
+	if strings.Contains(ident, "/") {
+		return Group{}, p.errorAt(identPos, fmt.Sprintf("navigation path '%s' is not supported", ident))
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op := strings.ToLower(p.readWhile(isODataLetter))
+	switch op {
+	case "eq", "ne", "gt", "ge", "lt", "le":
+		p.skipSpace()
+		lit, err := p.readLiteral()
+		if err != nil {
+			return Group{}, err
+		}
+		f, ok := odataComparison(ident, op, lit)
+		if !ok {
+			ferr := p.errorAt(opPos, fmt.Sprintf("operator '%s' cannot be used with null", op))
+			ferr.Field = ident
+			ferr.Operator = op
+			return Group{}, ferr
+		}
+		return Group{Logic: And, Filters: []Filter{f}}, nil
+
+	case "in":
+		values, err := p.readLiteralList()
+		if err != nil {
+			return Group{}, err
+		}
+		return Group{Logic: And, Filters: []Filter{{Field: ident, Operator: In, Value: strings.Join(values, ",")}}}, nil
+
+	case "":
+		return Group{}, p.errorAt(opPos, fmt.Sprintf("expected comparison operator after '%s'", ident))
+
+	default:
+		ferr := p.errorAt(opPos, fmt.Sprintf("operator '%s' is not supported", op))
+		ferr.Field = ident
+		ferr.Operator = op
+		return Group{}, ferr
+	}
+}
+
+// parseFunction handles contains/startswith/endswith(field,'value'), with an
+// optional trailing "eq true|false" / "ne true|false".
+func (p *odataParser) parseFunction(name string, namePos int) (Group, *FilterError) {
+	var clause Clause
+	switch strings.ToLower(name) {
+	case "contains":
+		clause = Contains
+	case "startswith":
+		clause = StartsWith
+	case "endswith":
+		clause = EndsWith
+	default:
+		return Group{}, p.errorAt(namePos, fmt.Sprintf("function '%s' is not supported", name))
+	}
+
+	p.pos++ // '('
+	p.skipSpace()
+	fieldPos := p.pos
+	field := p.readWhile(isODataIdent)
+	if field == "" || strings.Contains(field, "/") {
+		return Group{}, p.errorAt(fieldPos, fmt.Sprintf("%s expects a property name as its first argument", name))
+	}
+	p.skipSpace()
+	if p.peek() != ',' {
+		return Group{}, p.errorAt(p.pos, "expected ','")
+	}
+	p.pos++
+	p.skipSpace()
+	lit, err := p.readLiteral()
+	if err != nil {
+		return Group{}, err
+	}
+	p.skipSpace()
+	if p.peek() != ')' {
+		return Group{}, p.errorAt(p.pos, "expected ')'")
+	}
+	p.pos++
+
+	negate := false
+	save := p.pos
+	p.skipSpace()
+	if op := strings.ToLower(p.readWhile(isODataLetter)); op == "eq" || op == "ne" {
+		p.skipSpace()
+		boolPos := p.pos
+		b, perr := strconv.ParseBool(p.readWhile(isODataLiteral))
+		if perr != nil {
+			return Group{}, p.errorAt(boolPos, fmt.Sprintf("%s can only be compared with true or false", name))
+		}
+		negate = b == (op == "ne")
+	} else {
+		p.pos = save
+	}
+
+	f := Filter{Field: field, Operator: clause, Value: lit.value}
+	if negate && clause == Contains {
+		f.Operator = NotContains
+		negate = false
+	}
+	g := Group{Logic: And, Filters: []Filter{f}}
+	if negate {
+		g = negateGroup(g)
+	}
+	return g, nil
+}
+
+type odataLiteral struct {
+	value  string
+	isNull bool
+}
+
+func (p *odataParser) readLiteral() (odataLiteral, *FilterError) {
+	if p.peek() == '\'' {
+		v, err := p.readQuoted(true)
+		return odataLiteral{value: v}, err
+	}
+	pos := p.pos
+	v := p.readWhile(isODataLiteral)
+	if v == "" {
+		return odataLiteral{}, p.errorAt(pos, "expected literal value")
+	}
+	if p.peek() == '\'' || p.peek() == '(' {
+		return odataLiteral{}, p.errorAt(pos, fmt.Sprintf("unsupported literal '%s'", v))
+	}
+	return odataLiteral{value: v, isNull: v == "null"}, nil
+}
+
+func (p *odataParser) readLiteralList() ([]string, *FilterError) {
+	p.skipSpace()
+	if p.peek() != '(' {
+		return nil, p.errorAt(p.pos, "expected '(' after in")
+	}
+	p.pos++
+	var out []string
+	for {
+		p.skipSpace()
+		litPos := p.pos
+		lit, err := p.readLiteral()
+		if err != nil {
+			return nil, err
+		}
+		// Values are passed on comma-separated, so a quoted ',' would split
+		// one value in two.
+		if strings.Contains(lit.value, ",") {
+			return nil, p.errorAt(litPos, "in values cannot contain ','")
+		}
+		out = append(out, lit.value)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return out, nil
+		default:
+			return nil, p.errorAt(p.pos, "expected ',' or ')' in list")
+		}
+	}
+}
+
+func isODataLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isODataIdent(c byte) bool {
+	return isODataLetter(c) || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '/'
+}
+
+func isODataLiteral(c byte) bool {
+	return isODataLetter(c) || c >= '0' && c <= '9' || strings.IndexByte("-+.:_", c) >= 0
+}
+
+// odataComparison maps "field op literal" onto a Filter.
+func odataComparison(field, op string, lit odataLiteral) (Filter, bool) {
+	if lit.isNull {
+		switch op {
+		case "eq":
+			return Filter{Field: field, Operator: IsNull, Value: ""}, true
+		case "ne":
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, true
+		default:
+			return Filter{}, false
+		}
+	}
+	clauses := map[string]Clause{
+		"eq": Equals, "ne": NotEquals,
+		"gt": GreaterThan, "ge": GreaterThanOrEq,
+		"lt": LessThan, "le": LessThanOrEq,
+	}
+	return Filter{Field: field, Operator: clauses[op], Value: lit.value}, true
+}
@@ -0,0 +1,157 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseODataFilter(t *testing.T) {
+	g, err := ParseODataFilter("Price gt 10 and (contains(Name,'foo') or Status in ('a','b')) and not endswith(Name,'x')")
+	require.Nil(t, err)
+
+	assert.Equal(t, And, g.Logic)
+	assert.Equal(t, []Filter{{Field: "Price", Operator: GreaterThan, Value: "10"}}, g.Filters)
+	require.Len(t, g.Groups, 2)
+	assert.Equal(t, Or, g.Groups[0].Logic)
+	assert.Equal(t, []Filter{
+		{Field: "Name", Operator: Contains, Value: "foo"},
+		{Field: "Status", Operator: In, Value: "a,b"},
+	}, g.Groups[0].Filters)
+	assert.True(t, g.Groups[1].Not)
+}
+
+func TestParseODataFilter_Literals(t *testing.T) {
+	cases := map[string]Filter{
+		"Name eq 'O''Brien'":              {Field: "Name", Operator: Equals, Value: "O'Brien"},
+		"Email eq null":                   {Field: "Email", Operator: IsNull, Value: ""},
+		"Email ne null":                   {Field: "Email", Operator: IsNotNull, Value: ""},
+		"Created le 2025-01-01T00:00:00Z": {Field: "Created", Operator: LessThanOrEq, Value: "2025-01-01T00:00:00Z"},
+		"contains(Name,'li') eq false":    {Field: "Name", Operator: NotContains, Value: "li"},
+		"startswith(Name, 'al') eq true":  {Field: "Name", Operator: StartsWith, Value: "al"},
+		"Age ge -1":                       {Field: "Age", Operator: GreaterThanOrEq, Value: "-1"},
+	}
+	for in, want := range cases {
+		g, err := ParseODataFilter(in)
+		require.Nil(t, err, in)
+		assert.Equal(t, []Filter{want}, g.Filters, in)
+	}
+}
+
+func TestParseODataFilter_Unsupported(t *testing.T) {
+	cases := map[string]int{
+		"Price add 5 gt 10":           7,
+		"tolower(Name) eq 'x'":        1,
+		"Tags/any(t: t eq 'x')":       1,
+		"Author/Name eq 'x'":          1,
+		"Price gt null":               7,
+		"Price gt 10 and":             16,
+		"Created eq datetime'2020-1'": 12,
+	}
+	for in, col := range cases {
+		_, err := ParseODataFilter(in)
+		require.NotNil(t, err, in)
+		assert.Equal(t, col, err.Position, in)
+		assert.Equal(t, ErrorTypeParsing, err.Type, in)
+	}
+}
+
+func TestParseODataFilter_InValueWithComma(t *testing.T) {
+	_, err := ParseODataFilter("Name in ('x', 'a,b')")
+	require.NotNil(t, err)
+	assert.Equal(t, 15, err.Position)
+	assert.Equal(t, ErrorTypeParsing, err.Type)
+	assert.Contains(t, err.Message, "cannot contain ','")
+}
+
+func TestParseOData_Options(t *testing.T) {
+	q := url.Values{}
+	q.Set("$filter", "Age gt 18")
+	q.Set("$orderby", "Age desc,Name")
+	q.Set("$top", "5")
+	q.Set("$skip", "10")
+	q.Set("$count", "true")
+
+	opts, errs := ParseOData(q)
+	require.True(t, errs.OK(), "unexpected errors: %+v", errs)
+	assert.Equal(t, "-Age,Name", opts.OrderBy)
+	assert.Equal(t, 5, *opts.Top)
+	assert.Equal(t, 10, *opts.Skip)
+	assert.True(t, opts.Count)
+	assert.Len(t, opts.Filter.Filters, 1)
+
+	q = url.Values{}
+	q.Set("$select", "Name")
+	q.Set("$top", "-1")
+	_, errs = ParseOData(q)
+	assert.Equal(t, 2, errs.Len())
+}
+
+func TestBuilder_WithOData(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("$filter", "startswith(name,'al') or age lt 15")
+	q.Set("$orderby", "age desc")
+	q.Set("$top", "2")
+	q.Set("$skip", "1")
+	q.Set("$count", "true")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowAll("name", "age").
+		WithOData().
+		Apply()
+
+	var got []opUser
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	require.NoError(t, b.Query().Find(&got).Error)
+	// ALF(30), alina(22), alice(20), beta(10) -> skip 1, take 2
+	require.Len(t, got, 2)
+	assert.Equal(t, "alina", got[0].Name)
+	assert.Equal(t, "alice", got[1].Name)
+	assert.True(t, b.OData().Count)
+}
+
+func TestBuilder_WithOData_Paginate(t *testing.T) {
+	db := mustDB(t)
+
+	build := func(q url.Values) *Builder {
+		q.Set("$orderby", "age desc")
+		return NewFromValues(q, db.Model(&opUser{})).
+			AllowAll("age").
+			WithOData().
+			Paginate(2, 2).
+			Apply()
+	}
+
+	// $top and $skip become page[size] and page[number].
+	b := build(url.Values{"$top": {"2"}, "$skip": {"2"}})
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	// ALF(30), alina(22), alice(20), bob(17), beta(10)
+	require.Len(t, got, 2)
+	assert.Equal(t, "alice", got[0].Name)
+
+	p, errs := b.Pagination()
+	require.Nil(t, errs)
+	assert.Equal(t, PageMeta{Total: 5, Page: 2, PageSize: 2, Pages: 3}, p.Meta)
+	assert.NotContains(t, p.Links.Next, "%24skip")
+	assert.Contains(t, p.Links.Next, "page%5Bnumber%5D=3")
+
+	for q, param := range map[string]string{
+		"$top=100000": "$top",
+		"$top=0":      "$top",
+		"$skip=3":     "$skip",
+	} {
+		values, err := url.ParseQuery(q)
+		require.NoError(t, err)
+		b := build(values)
+		require.False(t, b.OK(), q)
+		assert.Equal(t, ReasonInvalidPage, b.GetErrors().First().Reason, q)
+		assert.Equal(t, param, b.GetErrors().First().Parameter, q)
+	}
+}
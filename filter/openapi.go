@@ -0,0 +1,186 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OpenAPIParameter is an OpenAPI 3.1 parameter object.
+type OpenAPIParameter struct {
+	Schema      map[string]any `json:"schema"`
+	Explode     *bool          `json:"explode,omitempty"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Style       string         `json:"style,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+}
+
+// OpenAPIParameters describes the query parameters the builder accepts, for
+// the parameters list of a list operation:
+//
+//   - one deepObject parameter per filterable field, e.g. "filter[price]"
+//     with a property per allowed operator ("filter[price][gt]=10")
+//   - "sort" as a comma-separated array of fields and their '-' variants
+//   - "page[number]" and "page[size]" when Paginate is used
+//   - "include" and "fields" when AllowIncludes and AllowSelect are used
+//
+// Value schemas come from FilterConfig.Type and Enum, or the model's column
+// types. Build it from the same Schema the endpoint uses:
+//
+//	params := users(filter.NewFromValues(nil, db.Model(&User{}))).OpenAPIParameters()
+func (b *Builder) OpenAPIParameters() []OpenAPIParameter {
+	explode, noExplode := true, false
+	var params []OpenAPIParameter
+
+	for _, spec := range b.fieldSpecs() {
+		props := make(map[string]any, len(spec.Operators))
+		for _, op := range spec.Operators {
+			props[string(op)] = operatorSchema(spec, op)
+		}
+		desc := spec.Description
+		if desc == "" {
+			desc = fmt.Sprintf("Filter by %s", spec.Field)
+		}
+		if slices.Contains(spec.Operators, Equals) {
+			desc += fmt.Sprintf(". filter[%s]=value is short for filter[%s][eq]=value", spec.Field, spec.Field)
+		}
+		params = append(params, OpenAPIParameter{
+			Name:        fmt.Sprintf("filter[%s]", spec.Field),
+			In:          "query",
+			Description: desc,
+			Required:    spec.Required,
+			Style:       "deepObject",
+			Explode:     &explode,
+			Schema: map[string]any{
+				"type":                 "object",
+				"properties":           props,
+				"additionalProperties": false,
+			},
+		})
+	}
+
+	if sorts := b.effectiveSorts(); len(sorts) > 0 {
+		desc := "Comma-separated sort fields; prefix with '-' for descending"
+		if b.defaultSort != "" {
+			desc += fmt.Sprintf(" (default %s)", b.defaultSort)
+		}
+		params = append(params, OpenAPIParameter{
+			Name:        "sort",
+			In:          "query",
+			Description: desc,
+			Style:       "form",
+			Explode:     &noExplode,
+			Schema: map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "enum": sortValues(sorts)},
+				"uniqueItems": true,
+			},
+		})
+	}
+
+	if b.defaultPageSize > 0 {
+		size := map[string]any{"type": "integer", "minimum": 1, "default": b.defaultPageSize}
+		if b.maxPageSize > 0 {
+			size["maximum"] = b.maxPageSize
+		}
+		params = append(params,
+			OpenAPIParameter{
+				Name:        "page[number]",
+				In:          "query",
+				Description: "1-based page number",
+				Schema:      map[string]any{"type": "integer", "minimum": 1, "default": 1},
+			},
+			OpenAPIParameter{
+				Name:        "page[size]",
+				In:          "query",
+				Description: "Page size",
+				Schema:      size,
+			},
+		)
+	}
+
+	if len(b.allowedIncludes) > 0 {
+		params = append(params, OpenAPIParameter{
+			Name:        "include",
+			In:          "query",
+			Description: "Comma-separated associations to include",
+			Style:       "form",
+			Explode:     &noExplode,
+			Schema: map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "enum": b.allowedIncludes},
+				"uniqueItems": true,
+			},
+		})
+	}
+
+	if len(b.allowedSelect) > 0 {
+		name := "fields"
+		if b.resource != "" {
+			name = fmt.Sprintf("fields[%s]", b.resource)
+		}
+		params = append(params, OpenAPIParameter{
+			Name:        name,
+			In:          "query",
+			Description: "Comma-separated fields to return",
+			Style:       "form",
+			Explode:     &noExplode,
+			Schema: map[string]any{
+				"type":        "array",
+				"items":       map[string]any{"type": "string", "enum": b.allowedSelect},
+				"uniqueItems": true,
+			},
+		})
+	}
+
+	return params
+}
+
+// MergeOpenAPIParameters adds params to the operation method (e.g. "get")
+// of path in the JSON OpenAPI document spec and returns the new document.
+// Parameters with the same name and location are replaced, others kept, so
+// merging again after a config change is safe. It fails when spec has no
+// such operation.
+func MergeOpenAPIParameters(spec []byte, path, method string, params []OpenAPIParameter) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	item, _ := paths[path].(map[string]any)
+	op, ok := item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("openapi: no %s operation for path %s", strings.ToLower(method), path)
+	}
+
+	var generated []any
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	if err := json.Unmarshal(raw, &generated); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	replaced := make(map[string]bool, len(params))
+	for _, p := range params {
+		replaced[p.In+"\x00"+p.Name] = true
+	}
+	existing, _ := op["parameters"].([]any)
+	merged := make([]any, 0, len(existing)+len(generated))
+	for _, p := range existing {
+		m, _ := p.(map[string]any)
+		in, _ := m["in"].(string)
+		name, _ := m["name"].(string)
+		if !replaced[in+"\x00"+name] {
+			merged = append(merged, p)
+		}
+	}
+	op["parameters"] = append(merged, generated...)
+
+	return json.MarshalIndent(doc, "", "  ")
+}
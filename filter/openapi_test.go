@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_OpenAPIParameters(t *testing.T) {
+	db := mustDB(t)
+
+	params := NewFromValues(nil, db.Model(&opUser{})).
+		AllowConfigs(
+			AllowedFilter("age", GreaterThan, Between),
+			AllowedFilter("name", Equals, In).WithEnum("alice", "bob").AsRequired(),
+			AllowedFilter("email", Equals).WithType(TypeString),
+		).
+		AllowSorts("name", "age").
+		Paginate(20, 100).
+		OpenAPIParameters()
+
+	byName := make(map[string]OpenAPIParameter, len(params))
+	for _, p := range params {
+		byName[p.Name] = p
+	}
+	require.Len(t, byName, 6)
+
+	age := byName["filter[age]"]
+	assert.Equal(t, "deepObject", age.Style)
+	assert.True(t, *age.Explode)
+	assert.False(t, age.Required)
+	props := age.Schema["properties"].(map[string]any)
+	assert.Len(t, props, 2)
+	assert.Equal(t, "integer", props["gt"].(map[string]any)["type"])
+	assert.Equal(t, "^[^,]+,[^,]+$", props["between"].(map[string]any)["pattern"])
+
+	name := byName["filter[name]"]
+	assert.True(t, name.Required)
+	assert.Equal(t, []string{"alice", "bob"}, name.Schema["properties"].(map[string]any)["eq"].(map[string]any)["enum"])
+
+	sort := byName["sort"]
+	assert.Equal(t, []string{"name", "-name", "age", "-age"}, sort.Schema["items"].(map[string]any)["enum"])
+	assert.False(t, *sort.Explode)
+
+	assert.Equal(t, 100, byName["page[size]"].Schema["maximum"])
+	assert.Equal(t, 20, byName["page[size]"].Schema["default"])
+	assert.Contains(t, byName, "page[number]")
+}
+
+func TestMergeOpenAPIParameters(t *testing.T) {
+	spec := []byte(`{
+		"openapi": "3.1.0",
+		"paths": {"/users": {"get": {"parameters": [
+			{"name": "X-Tenant", "in": "header", "schema": {"type": "string"}},
+			{"name": "sort", "in": "query", "schema": {"type": "string"}}
+		]}}}
+	}`)
+	params := NewFromValues(nil, nil).AllowFields("name").AllowSorts("name").OpenAPIParameters()
+
+	out, err := MergeOpenAPIParameters(spec, "/users", "GET", params)
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Parameters []OpenAPIParameter `json:"parameters"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	got := doc.Paths["/users"]["get"].Parameters
+	require.Len(t, got, 3)
+	assert.Equal(t, "X-Tenant", got[0].Name)
+	assert.Equal(t, "filter[name]", got[1].Name)
+	assert.Equal(t, "sort", got[2].Name)
+	assert.Equal(t, "array", got[2].Schema["type"])
+
+	_, err = MergeOpenAPIParameters(spec, "/orders", "get", params)
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Pagination is the metadata of a list response, ready to be embedded next
+// to the data:
+//
+//	{
+//	  "data": [...],
+//	  "meta":  {"total": 120, "page": 2, "page_size": 20, "pages": 6},
+//	  "links": {"self": "/products?page%5Bnumber%5D=2&page%5Bsize%5D=20",
+//	            "first": "...", "prev": "...", "next": "...", "last": "..."}
+//	}
+//
+// When the total is an estimate, Estimated is true and there is no last link.
+type Pagination struct {
+	Links PageLinks `json:"links"`
+	Meta  PageMeta  `json:"meta"`
+}
+
+// PageMeta holds the totals. Page fields are zero unless Paginate was used.
+type PageMeta struct {
+	Total     int64 `json:"total"`
+	Page      int   `json:"page,omitempty"`
+	PageSize  int   `json:"page_size,omitempty"`
+	Pages     int   `json:"pages,omitempty"`
+	Estimated bool  `json:"estimated,omitempty"`
+}
+
+// PageLinks are JSON:API/HAL style navigation links.
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// Paginate enables page[number] and page[size] (default defaultSize, at
+// most maxSize; zero means no maximum). Apply adds the matching LIMIT and
+// OFFSET to Query. With WithOData, $top and $skip set the page size and
+// number instead, within the same limits.
+func (b *Builder) Paginate(defaultSize, maxSize int) *Builder {
+	b.defaultPageSize = defaultSize
+	b.maxPageSize = maxSize
+	return b
+}
+
+// EstimateCountAbove makes Count use the PostgreSQL planner estimate when
+// it is at least threshold rows, instead of an exact COUNT(*). Queries with
+// no WHERE clause at all (including scope and base query conditions) use
+// pg_class.reltuples, others EXPLAIN. Other databases always count exactly.
+func (b *Builder) EstimateCountAbove(threshold int64) *Builder {
+	b.estimateAbove = threshold
+	return b
+}
+
+// applyPage parses page[number]/page[size] and limits q accordingly.
+func (b *Builder) applyPage(q *gorm.DB) *gorm.DB {
+	b.page, b.pageSize = 1, b.defaultPageSize
+	if v := strings.TrimSpace(b.values.Get("page[number]")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			b.reject(NewInvalidPageError("page[number]", v, "must be a positive integer").at("page[number]"))
+		} else {
+			b.page = n
+		}
+	}
+	if v := strings.TrimSpace(b.values.Get("page[size]")); v != "" {
+		n, err := strconv.Atoi(v)
+		switch {
+		case err != nil || n < 1:
+			b.reject(NewInvalidPageError("page[size]", v, "must be a positive integer").at("page[size]"))
+		case b.maxPageSize > 0 && n > b.maxPageSize:
+			b.reject(NewInvalidPageError("page[size]", v, fmt.Sprintf("must be at most %d", b.maxPageSize)).at("page[size]"))
+		default:
+			b.pageSize = n
+		}
+	}
+	if b.odata != nil {
+		b.applyODataPage()
+	}
+	// page*size must not overflow, or OFFSET turns negative.
+	if maxPage := math.MaxInt / b.pageSize; b.page > maxPage {
+		b.reject(NewInvalidPageError("page[number]", strconv.Itoa(b.page), fmt.Sprintf("must be at most %d", maxPage)).at("page[number]"))
+		b.page = 1
+	}
+	return q.Limit(b.pageSize).Offset((b.page - 1) * b.pageSize)
+}
+
+// applyODataPage maps $top and $skip onto the page size and number, so that
+// they obey the same limits and the pagination links describe the page
+// returned. $skip must be a multiple of the page size.
+func (b *Builder) applyODataPage() {
+	if top := b.odata.Top; top != nil {
+		v := strconv.Itoa(*top)
+		switch {
+		case *top < 1:
+			b.reject(NewInvalidPageError("$top", v, "must be a positive integer").at("$top"))
+		case b.maxPageSize > 0 && *top > b.maxPageSize:
+			b.reject(NewInvalidPageError("$top", v, fmt.Sprintf("must be at most %d", b.maxPageSize)).at("$top"))
+		default:
+			b.pageSize = *top
+		}
+	}
+	if skip := b.odata.Skip; skip != nil {
+		if *skip%b.pageSize != 0 {
+			v := strconv.Itoa(*skip)
+			b.reject(NewInvalidPageError("$skip", v, fmt.Sprintf("must be a multiple of the page size %d", b.pageSize)).at("$skip"))
+		} else {
+			b.page = *skip/b.pageSize + 1
+		}
+	}
+}
+
+// Count returns the number of rows matching the filters, ignoring sort,
+// includes, fieldsets and paging. estimated reports a planner estimate, see
+// EstimateCountAbove.
+func (b *Builder) Count() (total int64, estimated bool, ferr *FilterError) {
+	if b.result == nil {
+		b.Apply()
+	}
+	if !b.OK() {
+		return 0, false, b.GetErrors().First()
+	}
+
+	if b.estimateAbove > 0 && detectDatabaseDriver(b.filtered) == PostgreSQL {
+		if est, ok := b.estimateCount(); ok && est >= b.estimateAbove {
+			return est, true, nil
+		}
+	}
+
+	if err := b.filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, false, NewDatabaseError("Count query failed", err)
+	}
+	return total, false, nil
+}
+
+// estimateCount asks PostgreSQL for its row estimate. ok is false when the
+// planner has no usable statistics.
+func (b *Builder) estimateCount() (int64, bool) {
+	db := b.filtered.Session(&gorm.Session{})
+	stmt := db.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{}).Statement
+
+	if unfiltered(stmt) {
+		var est float64
+		err := db.Session(&gorm.Session{NewDB: true}).
+			Raw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", stmt.Table).
+			Scan(&est).Error
+		return int64(est), err == nil && est >= 0
+	}
+
+	var plan string
+	if err := explain(db).Row().Scan(&plan); err != nil {
+		return 0, false
+	}
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if json.Unmarshal([]byte(plan), &explain) != nil || len(explain) == 0 {
+		return 0, false
+	}
+	return int64(explain[0].Plan.Rows), true
+}
+
+// explain returns the EXPLAIN (FORMAT JSON) statement of q. q is passed as
+// a subquery so that GORM renders it and binds its variables in the
+// dialect's placeholder style.
+func explain(q *gorm.DB) *gorm.DB {
+	return q.Session(&gorm.Session{NewDB: true}).Raw("EXPLAIN (FORMAT JSON) ?", q)
+}
+
+// unfiltered reports whether the built statement selects every row of its
+// table, so that the table's row estimate is its count. Any WHERE clause
+// rules it out, whether from client filters, WithScope, the caller's base
+// query or soft deletes, as do joins and grouping.
+func unfiltered(stmt *gorm.Statement) bool {
+	_, where := stmt.Clauses["WHERE"]
+	_, group := stmt.Clauses["GROUP BY"]
+	return !where && !group && len(stmt.Joins) == 0
+}
+
+// Pagination counts the filtered rows and builds the response metadata and
+// links. Links keep every other query parameter of the request.
+func (b *Builder) Pagination() (*Pagination, *FilterErrors) {
+	total, estimated, err := b.Count()
+	if err != nil {
+		if !b.OK() {
+			return nil, b.GetErrors()
+		}
+		errs := &FilterErrors{}
+		errs.Add(err)
+		return nil, errs
+	}
+
+	p := &Pagination{Meta: PageMeta{Total: total, Estimated: estimated}}
+	p.Links.Self = b.pageLink(0)
+	if b.pageSize <= 0 {
+		return p, nil
+	}
+
+	p.Meta.Page = b.page
+	p.Meta.PageSize = b.pageSize
+	p.Meta.Pages = int((total + int64(b.pageSize) - 1) / int64(b.pageSize))
+	p.Links.Self = b.pageLink(b.page)
+	p.Links.First = b.pageLink(1)
+	if b.page > 1 {
+		p.Links.Prev = b.pageLink(b.page - 1)
+	}
+	if int64(b.page)*int64(b.pageSize) < total {
+		p.Links.Next = b.pageLink(b.page + 1)
+	}
+	if !estimated && p.Meta.Pages > 0 {
+		p.Links.Last = b.pageLink(p.Meta.Pages)
+	}
+	return p, nil
+}
+
+// pageLink returns the request URL with page[number] set to page (when
+// positive). Without a request the link is relative ("?...").
+func (b *Builder) pageLink(page int) string {
+	values := url.Values{}
+	for k, v := range b.values {
+		values[k] = v
+	}
+	if page > 0 {
+		values.Set("page[number]", strconv.Itoa(page))
+		values.Set("page[size]", strconv.Itoa(b.pageSize))
+		values.Del("$top")
+		values.Del("$skip")
+	}
+
+	path := ""
+	if b.req != nil {
+		path = b.req.URL.Path
+	}
+	if len(values) == 0 {
+		return path
+	}
+	return path + "?" + values.Encode()
+}
@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestBuilder_Pagination(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[age][gte]", "17")
+	q.Set("sort", "-age")
+	q.Set("page[number]", "2")
+	q.Set("page[size]", "3")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowAll("age").
+		Paginate(10, 50).
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 1)
+	assert.Equal(t, "bob", got[0].Name)
+
+	p, errs := b.Pagination()
+	require.Nil(t, errs)
+	assert.Equal(t, PageMeta{Total: 4, Page: 2, PageSize: 3, Pages: 2}, p.Meta)
+	assert.Contains(t, p.Links.Self, "page%5Bnumber%5D=2")
+	assert.Contains(t, p.Links.Prev, "page%5Bnumber%5D=1")
+	assert.Contains(t, p.Links.Last, "page%5Bnumber%5D=2")
+	assert.Contains(t, p.Links.First, "sort=-age")
+	assert.Empty(t, p.Links.Next)
+}
+
+func TestBuilder_Count_WithoutPaging(t *testing.T) {
+	db := mustDB(t)
+
+	c := newRequestWithQuery(url.Values{"filter[name][starts-with]": {"al"}, "sort": {"name"}})
+	b := NewFromRequest(c, db.Model(&opUser{})).AllowAll("name").Apply()
+
+	total, estimated, err := b.Count()
+	require.Nil(t, err)
+	assert.EqualValues(t, 3, total)
+	assert.False(t, estimated)
+}
+
+func TestBuilder_Paginate_Invalid(t *testing.T) {
+	db := mustDB(t)
+
+	for _, q := range []url.Values{
+		{"page[number]": {"0"}},
+		{"page[size]": {"abc"}},
+		{"page[size]": {"500"}},
+		{"page[number]": {"9223372036854775807"}},
+	} {
+		c := newRequestWithQuery(q)
+		b := NewFromRequest(c, db.Model(&opUser{})).Paginate(10, 100).Apply()
+		require.False(t, b.OK(), "%v", q)
+		assert.Contains(t, b.GetErrors().First().Field, "page[")
+	}
+}
+
+func TestUnfiltered(t *testing.T) {
+	db := mustDB(t)
+
+	stmt := func(b *Builder) *gorm.Statement {
+		b.Apply()
+		return b.filtered.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]any{}).Statement
+	}
+
+	assert.True(t, unfiltered(stmt(NewFromValues(nil, db.Model(&opUser{})).AllowAll("age"))))
+	assert.False(t, unfiltered(stmt(NewFromValues(url.Values{"filter[age][gt]": {"18"}}, db.Model(&opUser{})).AllowAll("age"))))
+	// Scope filters and the base query's conditions restrict the rows too,
+	// so the table-wide estimate must not be used.
+	assert.False(t, unfiltered(stmt(NewFromValues(nil, db.Model(&opUser{})).
+		AllowAll("age").
+		WithScope(Filter{Field: "name", Operator: Equals, Value: "alice"}))))
+	assert.False(t, unfiltered(stmt(NewFromValues(nil, db.Model(&opUser{}).Where("age > ?", 18)).AllowAll("age"))))
+}
+
+// The EXPLAIN of the count estimate must bind the filter values with
+// PostgreSQL placeholders. DryRun renders it without a server.
+func TestExplain_PostgreSQL(t *testing.T) {
+	pg, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	b := NewFromValues(url.Values{"filter[age][between]": {"18,30"}}, pg.Model(&opUser{}).Where("name <> ?", "bob")).
+		AllowAll("age").
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	stmt := explain(b.filtered).Find(&[]map[string]any{}).Statement
+	assert.Equal(t, `EXPLAIN (FORMAT JSON) SELECT * FROM "op_users" WHERE name <> $1 AND (age BETWEEN $2 AND $3)`, stmt.SQL.String())
+	assert.Equal(t, []any{"bob", "18", "30"}, stmt.Vars)
+}
@@ -8,6 +8,8 @@ import (
 // Parser handles parsing of URL query parameters into filters.
 type Parser struct {
 	queryValues url.Values
+	// Optional: compact single-parameter syntax, see WithCompact
+	compact *CompactSyntax
 	// Optional: key prefix, defaults to "filter"
 	prefix string
 }
@@ -38,6 +40,8 @@ func (p *Parser) WithPrefix(prefix string) *Parser {
 // Supports both:
 //  1. JSON:   filter[field][operator]=value
 //  2. Simple: filter[field]=value    (assumes Equals)
+//
+// plus the compact syntax when enabled with WithCompact.
 func (p *Parser) Parse() *ParseResult {
 	res := &ParseResult{
 		Errors:  &FilterErrors{},
@@ -55,7 +59,7 @@ func (p *Parser) Parse() *ParseResult {
 
 		// Fast path: reject keys that don't end with ']'
 		if !strings.HasSuffix(key, prefixClose) {
-			res.Errors.Add(NewInvalidFilterFormatError(key, val))
+			res.Errors.Add(NewInvalidFilterFormatError(key, val).at(key))
 			continue
 		}
 
@@ -67,13 +71,14 @@ func (p *Parser) Parse() *ParseResult {
 			// Simple format: filter[field]=value
 			field := strings.TrimSpace(parts[0])
 			if field == "" {
-				res.Errors.Add(NewValidationError("", "", val, "Empty field name in filter"))
+				res.Errors.Add(NewEmptyFieldError("", val).at(key))
 				continue
 			}
 			res.Filters = append(res.Filters, Filter{
-				Field:    field,
-				Operator: Equals,
-				Value:    val,
+				Field:     field,
+				Operator:  Equals,
+				Value:     val,
+				Parameter: key,
 			})
 
 		case 2:
@@ -81,25 +86,30 @@ func (p *Parser) Parse() *ParseResult {
 			field := strings.TrimSpace(parts[0])
 			opStr := strings.TrimSpace(parts[1])
 			if field == "" || opStr == "" {
-				res.Errors.Add(NewInvalidFilterFormatError(key, val))
+				res.Errors.Add(NewInvalidFilterFormatError(key, val).at(key))
 				continue
 			}
 			clause := Clause(opStr)
 			if !clause.IsValid() {
-				res.Errors.Add(NewInvalidOperatorError(opStr))
+				res.Errors.Add(NewInvalidOperatorError(opStr).at(key))
 				continue
 			}
 			res.Filters = append(res.Filters, Filter{
-				Field:    field,
-				Operator: clause,
-				Value:    val,
+				Field:     field,
+				Operator:  clause,
+				Value:     val,
+				Parameter: key,
 			})
 
 		default:
 			// Anything else is malformed: filter[field][op][extra]...
-			res.Errors.Add(NewInvalidFilterFormatError(key, val))
+			res.Errors.Add(NewInvalidFilterFormatError(key, val).at(key))
 		}
 	}
 
+	if p.compact != nil {
+		p.parseCompact(res)
+	}
+
 	return res
 }
@@ -0,0 +1,76 @@
+package filter
+
+import "context"
+
+// Authorizer reports whether the caller of ctx holds permission. It is
+// typically backed by the roles or scopes of the authenticated user.
+type Authorizer func(ctx context.Context, permission string) bool
+
+// WithAuthorizer resolves the permissions declared with
+// FilterConfig.RequirePermissions and RequireOperatorPermissions. Without
+// an authorizer every declared permission is denied.
+func (b *Builder) WithAuthorizer(authorize Authorizer) *Builder {
+	b.authorize = authorize
+	return b
+}
+
+// WithContext sets the request context passed to the Authorizer. Builders
+// created with NewFromRequest (or ginfilter.New) default to the request's
+// context.
+func (b *Builder) WithContext(ctx context.Context) *Builder {
+	b.reqCtx = ctx
+	return b
+}
+
+// context returns the request context for authorization.
+func (b *Builder) context() context.Context {
+	if b.reqCtx != nil {
+		return b.reqCtx
+	}
+	if b.req != nil {
+		return b.req.Context()
+	}
+	return context.Background()
+}
+
+// checkPermissions rejects client filters whose field or operator requires
+// a permission the caller lacks. Each field/operator pair is reported once.
+func (b *Builder) checkPermissions(expr Group) {
+	if len(b.configs) == 0 {
+		return
+	}
+	reported := make(map[string]bool)
+	for _, f := range expr.AllFilters() {
+		key := f.Field + "\x00" + string(f.Operator)
+		if reported[key] {
+			continue
+		}
+		if err := b.authorizeFilter(f); err != nil {
+			reported[key] = true
+			b.result.AddError(err.at(f.Parameter))
+		}
+	}
+}
+
+func (b *Builder) authorizeFilter(f Filter) *FilterError {
+	for _, c := range b.configs {
+		if c.Field != f.Field {
+			continue
+		}
+		for _, p := range c.Permissions {
+			if !b.allowed(p) {
+				return NewPermissionError(f.Field, "", p)
+			}
+		}
+		for _, p := range c.OperatorPermissions[f.Operator] {
+			if !b.allowed(p) {
+				return NewPermissionError(f.Field, string(f.Operator), p)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) allowed(permission string) bool {
+	return b.authorize != nil && b.authorize(b.context(), permission)
+}
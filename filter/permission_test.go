@@ -0,0 +1,64 @@
+package filter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roleKey struct{}
+
+func roleAuthorizer(ctx context.Context, permission string) bool {
+	role, _ := ctx.Value(roleKey{}).(string)
+	return role == "admin" || (role == "support" && permission == "users:read-email")
+}
+
+func TestBuilder_Permissions(t *testing.T) {
+	db := mustDB(t)
+
+	configs := []FilterConfig{
+		AllowedFilter("name", Equals),
+		AllowedFilter("email", Equals, Contains).
+			RequirePermissions("users:read-email").
+			RequireOperatorPermissions(Contains, "users:search-email"),
+	}
+	build := func(role string, q url.Values) *Builder {
+		ctx := context.WithValue(context.Background(), roleKey{}, role)
+		return NewFromValues(q, db.Model(&opUser{})).
+			AllowConfigs(configs...).
+			WithAuthorizer(roleAuthorizer).
+			WithContext(ctx).
+			Apply()
+	}
+
+	eq := url.Values{"filter[email]": {"a@x"}}
+	like := url.Values{"filter[email][like]": {"@x"}}
+
+	assert.True(t, build("admin", like).OK())
+	assert.True(t, build("support", eq).OK())
+	assert.True(t, build("public", url.Values{"filter[name]": {"bob"}}).OK())
+
+	b := build("support", like)
+	require.False(t, b.OK())
+	assert.Equal(t, ErrorTypePermission, b.GetErrors().First().Type)
+	assert.Equal(t, "like", b.GetErrors().First().Operator)
+	assert.Equal(t, http.StatusForbidden, b.GetErrors().Status())
+
+	b = build("public", eq)
+	require.False(t, b.OK())
+	assert.Equal(t, CodeFilterPermission, b.GetErrors().First().Code)
+}
+
+func TestBuilder_Permissions_NoAuthorizer(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(url.Values{"filter[email]": {"a@x"}}, db.Model(&opUser{})).
+		AllowConfigs(AllowedFilter("email").RequirePermissions("users:read-email")).
+		Apply()
+	require.False(t, b.OK())
+	assert.Equal(t, http.StatusForbidden, b.GetErrors().First().Status())
+}
@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details.
+const ProblemContentType = "application/problem+json"
+
+// DefaultProblemTypeBase prefixes the problem type URI of each ErrorCode,
+// e.g. "urn:problem-type:golens:filter-validation-error".
+const DefaultProblemTypeBase = "urn:problem-type:golens:"
+
+// ErrorFormat selects how Builder.ErrorResponse renders errors.
+type ErrorFormat string
+
+const (
+	// FormatDefault renders FilterErrors.ToJSONResponse as application/json.
+	FormatDefault ErrorFormat = "default"
+	// FormatProblem renders an RFC 9457 application/problem+json document.
+	FormatProblem ErrorFormat = "problem"
+	// FormatJSONAPI renders JSON:API error objects (FilterErrors.ToJSONAPI).
+	FormatJSONAPI ErrorFormat = "jsonapi"
+)
+
+// Problem is an RFC 9457 problem details document. The individual errors are
+// listed in the "errors" extension member:
+//
+//	{
+//	  "type": "urn:problem-type:golens:filter-validation-error",
+//	  "title": "Invalid filter",
+//	  "status": 400,
+//	  "detail": "Field 'secret' is not allowed for filtering",
+//	  "instance": "/users?filter[secret]=x",
+//	  "errors": [
+//	    {"detail": "Field 'secret' is not allowed for filtering",
+//	     "parameter": "filter[secret]", "code": "FILTER_VALIDATION_ERROR",
+//	     "reason": "FIELD_NOT_ALLOWED", "field": "secret"}
+//	  ]
+//	}
+//
+// When the errors have different codes, type is "about:blank" and title the
+// HTTP status text, as the RFC recommends.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemError `json:"errors"`
+}
+
+// ProblemError is one entry of Problem.Errors. Parameter names the offending
+// query parameter, e.g. "filter[price][gt]" or "sort".
+type ProblemError struct {
+	Detail      string      `json:"detail"`
+	Parameter   string      `json:"parameter,omitempty"`
+	Code        ErrorCode   `json:"code"`
+	Reason      ErrorReason `json:"reason,omitempty"`
+	Field       string      `json:"field,omitempty"`
+	Operator    string      `json:"operator,omitempty"`
+	Value       string      `json:"value,omitempty"`
+	Suggestions []string    `json:"suggestions,omitempty"`
+	Position    int         `json:"position,omitempty"`
+}
+
+// problemTitles are the short, stable summaries of each ErrorCode.
+var problemTitles = map[ErrorCode]string{
+	CodeFilterValidation: "Invalid filter",
+	CodeFilterParsing:    "Malformed filter",
+	CodeFilterConfig:     "Filter misconfigured",
+	CodeFilterDatabase:   "Filter query failed",
+	CodeFilterInternal:   "Internal filter error",
+	CodeFilterPermission: "Filter not permitted",
+	CodeFilterComplexity: "Filter too complex",
+}
+
+// ProblemType returns the problem type URI of code under base (see
+// DefaultProblemTypeBase).
+func ProblemType(base string, code ErrorCode) string {
+	return base + strings.ToLower(strings.ReplaceAll(string(code), "_", "-"))
+}
+
+// WithErrorFormat selects the format of ErrorResponse (default FormatDefault).
+func (b *Builder) WithErrorFormat(format ErrorFormat) *Builder {
+	b.errorFormat = format
+	return b
+}
+
+// WithProblemTypeBase sets the prefix of problem type URIs, e.g.
+// "https://api.example.com/problems/".
+func (b *Builder) WithProblemTypeBase(base string) *Builder {
+	b.problemTypeBase = base
+	return b
+}
+
+// ErrorResponse renders errs (usually GetErrors, or the errors of Aggregate
+// or Facets) in the builder's ErrorFormat:
+//
+//	if !b.OK() {
+//		status, contentType, body := b.ErrorResponse(b.GetErrors())
+//		c.Header("Content-Type", contentType)
+//		c.JSON(status, body)
+//		return
+//	}
+//
+// Problem documents use the request URI as instance.
+func (b *Builder) ErrorResponse(errs *FilterErrors) (status int, contentType string, body any) {
+	switch b.errorFormat {
+	case FormatProblem:
+		base := b.problemTypeBase
+		if base == "" {
+			base = DefaultProblemTypeBase
+		}
+		instance := ""
+		if b.req != nil {
+			instance = b.req.URL.RequestURI()
+		}
+		return errs.Status(), ProblemContentType, errs.ToProblem(base, instance)
+	case FormatJSONAPI:
+		return errs.Status(), JSONAPIContentType, errs.ToJSONAPI()
+	default:
+		return errs.Status(), "application/json", errs.ToJSONResponse()
+	}
+}
+
+// problemSummary picks the type and title of a problem document.
+func problemSummary(base string, status int, errs []*FilterError) (typ, title string) {
+	if len(errs) == 0 {
+		return "about:blank", http.StatusText(status)
+	}
+	code := errs[0].Code
+	for _, e := range errs[1:] {
+		if e.Code != code {
+			return "about:blank", http.StatusText(status)
+		}
+	}
+	title, ok := problemTitles[code]
+	if !ok {
+		title = http.StatusText(status)
+	}
+	return ProblemType(base, code), title
+}
+
+// problemDetail summarizes errs in one sentence.
+func problemDetail(errs []*FilterError) string {
+	switch len(errs) {
+	case 0:
+		return ""
+	case 1:
+		return errs[0].Message
+	}
+	return fmt.Sprintf("The request has %d filter errors", len(errs))
+}
@@ -0,0 +1,111 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_ErrorResponse_Problem(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter[secret]", "x")
+	q.Set("filter[name][between]", "a")
+	q.Set("sort", "nmae")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowConfigs(AllowedFilter("name", Between)).
+		AllowSorts("name").
+		WithErrorFormat(FormatProblem).
+		Apply()
+	require.False(t, b.OK())
+
+	status, contentType, body := b.ErrorResponse(b.GetErrors())
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, ProblemContentType, contentType)
+
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	assert.Equal(t, "urn:problem-type:golens:filter-validation-error", doc["type"])
+	assert.Equal(t, "Invalid filter", doc["title"])
+	assert.EqualValues(t, 400, doc["status"])
+	assert.Equal(t, "The request has 3 filter errors", doc["detail"])
+	assert.Equal(t, c.URL.RequestURI(), doc["instance"])
+
+	params := map[string]string{}
+	for _, item := range doc["errors"].([]any) {
+		e := item.(map[string]any)
+		params[e["reason"].(string)] = e["parameter"].(string)
+	}
+	assert.Equal(t, map[string]string{
+		"FIELD_NOT_ALLOWED": "filter[secret]",
+		"INVALID_RANGE":     "filter[name][between]",
+		"SORT_NOT_ALLOWED":  "sort",
+	}, params)
+}
+
+func TestFilterErrors_ToProblem_MixedCodes(t *testing.T) {
+	errs := &FilterErrors{}
+	errs.Add(NewFieldNotAllowedError("secret", []string{"name"}))
+	errs.Add(NewPermissionError("email", "", "users:read-email"))
+
+	p := errs.ToProblem("https://api.example.com/problems/", "")
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, http.StatusText(http.StatusBadRequest), p.Title)
+	assert.Len(t, p.Errors, 2)
+
+	errs = &FilterErrors{}
+	errs.Add(NewPermissionError("email", "", "users:read-email"))
+	p = errs.ToProblem("https://api.example.com/problems/", "/users")
+	assert.Equal(t, "https://api.example.com/problems/filter-permission-denied", p.Type)
+	assert.Equal(t, http.StatusForbidden, p.Status)
+	assert.Equal(t, "/users", p.Instance)
+}
+
+// Errors whose input did not come from a query parameter have no source.
+func TestBuilder_ErrorResponse_Problem_Parameter(t *testing.T) {
+	db := mustDB(t)
+
+	for name, tc := range map[string]struct {
+		query url.Values
+		build func(*Builder) *Builder
+		want  string
+	}{
+		"mongo": {
+			build: func(b *Builder) *Builder { return b.WithMongo([]byte(`{"secret":"x"}`)) },
+		},
+		"aip filter": {
+			build: func(b *Builder) *Builder { return b.WithAIP(`secret = "x"`, "") },
+		},
+		"aip order_by": {
+			build: func(b *Builder) *Builder { return b.WithAIP("", "secret desc") },
+		},
+		"required": {
+			build: func(b *Builder) *Builder { return b.AllowConfigs(AllowedFilter("age", Equals).AsRequired()) },
+		},
+		"odata": {
+			query: url.Values{"$filter": {"secret eq 'x'"}},
+			build: func(b *Builder) *Builder { return b.WithOData() },
+			want:  "$filter",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := tc.build(NewFromValues(tc.query, db.Model(&opUser{})).
+				AllowConfigs(AllowedFilter("name", Equals)).
+				AllowSorts("name")).
+				Apply()
+			require.False(t, b.OK())
+			p := b.GetErrors().ToProblem("", "")
+			require.Len(t, p.Errors, 1, "%+v", p.Errors)
+			assert.Equal(t, tc.want, p.Errors[0].Parameter)
+		})
+	}
+}
@@ -6,9 +6,11 @@ import (
 
 // Result represents the outcome of filter operations
 type Result struct {
-	Query   *gorm.DB      `json:"-"`
-	Errors  *FilterErrors `json:"errors,omitempty"`
-	Success bool          `json:"success"`
+	Query  *gorm.DB      `json:"-"`
+	Errors *FilterErrors `json:"errors,omitempty"`
+	// Warnings are problems that were dropped instead of failing (ModeWarn).
+	Warnings []*FilterError `json:"warnings,omitempty"`
+	Success  bool           `json:"success"`
 }
 
 func NewResult(query *gorm.DB) *Result {
@@ -46,6 +48,11 @@ func (r *Result) AddErrors(errors ...*FilterError) {
 	}
 }
 
+// AddWarning records a non-fatal problem; it does not affect OK().
+func (r *Result) AddWarning(err *FilterError) {
+	r.Warnings = append(r.Warnings, err)
+}
+
 // ✅ Unified: just OK()
 func (r *Result) OK() bool {
 	return r.Errors == nil || !r.Errors.HasErrors()
@@ -72,5 +79,8 @@ func (r *Result) ToJSONResponse() map[string]any {
 	if !r.OK() {
 		response["errors"] = r.Errors.ToJSONResponse()["errors"]
 	}
+	if len(r.Warnings) > 0 {
+		response["warnings"] = (&FilterErrors{Errors: r.Warnings}).ToJSONResponse()["errors"]
+	}
 	return response
 }
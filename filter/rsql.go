@@ -0,0 +1,300 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseRSQL parses an RSQL/FIQL expression into a Group.
+//
+// Grammar (whitespace is allowed around tokens):
+//
+//	or         = and { ("," | " or ") and }
+//	and        = constraint { (";" | " and ") constraint }
+//	constraint = "(" or ")" | comparison
+//	comparison = selector operator arguments
+//	arguments  = "(" value { "," value } ")" | value
+//
+// AND binds tighter than OR. Comparison operators map onto Clause values:
+//
+//	==          eq (like / starts-with / ends-with when the value uses * wildcards)
+//	!=          ne (not-like for *value*)
+//	=gt=  >     gt          =ge=  >=    gte
+//	=lt=  <     lt          =le=  <=    lte
+//	=in=        in          =out=       not-in
+//	=like=      like        =notlike=   not-like
+//	=isnull=    null / not-null for true / false
+//	=<clause>=  any other Clause, e.g. =between=(10,20)
+//
+// Syntax errors carry the 1-based column of the problem in FilterError.Position.
+func ParseRSQL(input string) (Group, *FilterError) {
+	p := &rsqlParser{scanner{input: input}}
+	g, err := p.parseOr()
+	if err != nil {
+		return Group{}, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return Group{}, p.errorAt(p.pos, fmt.Sprintf("unexpected character '%c'", p.peek()))
+	}
+	return g, nil
+}
+
+type rsqlParser struct {
+	scanner
+}
+
+func (p *rsqlParser) parseOr() (Group, *FilterError) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for p.acceptSeparator(',', "or") {
+		next, err := p.parseAnd()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(Or, terms), nil
+}
+
+func (p *rsqlParser) parseAnd() (Group, *FilterError) {
+	first, err := p.parseConstraint()
+	if err != nil {
+		return Group{}, err
+	}
+	terms := []Group{first}
+	for p.acceptSeparator(';', "and") {
+		next, err := p.parseConstraint()
+		if err != nil {
+			return Group{}, err
+		}
+		terms = append(terms, next)
+	}
+	return joinGroups(And, terms), nil
+}
+
+// acceptSeparator consumes either the symbolic separator or the
+// whitespace-delimited keyword form ("a==1 and b==2").
+func (p *rsqlParser) acceptSeparator(symbol byte, keyword string) bool {
+	p.skipSpace()
+	if p.peek() == symbol {
+		p.pos++
+		return true
+	}
+	if p.pos == 0 || !isSpace(p.input[p.pos-1]) {
+		return false
+	}
+	return p.acceptKeyword(keyword, true)
+}
+
+func (p *rsqlParser) parseConstraint() (Group, *FilterError) {
+	p.skipSpace()
+	if p.eof() {
+		return Group{}, p.errorAt(p.pos, "unexpected end of expression, expected a comparison")
+	}
+	if p.peek() == '(' {
+		p.pos++
+		g, err := p.parseOr()
+		if err != nil {
+			return Group{}, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return Group{}, p.errorAt(p.pos, "expected ')'")
+		}
+		p.pos++
+		return g, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *rsqlParser) parseComparison() (Group, *FilterError) {
+	selector := p.readWhile(func(c byte) bool { return !isRSQLReserved(c) })
+	if selector == "" {
+		return Group{}, p.errorAt(p.pos, "expected field name")
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	op, ok := p.readOperator()
+	if !ok {
+		return Group{}, p.errorAt(opPos, "expected comparison operator")
+	}
+
+	p.skipSpace()
+	argPos := p.pos
+	args, err := p.readArguments()
+	if err != nil {
+		return Group{}, err
+	}
+
+	f, msg, atOperator := rsqlFilter(selector, op, args)
+	if msg != "" {
+		pos := argPos
+		if atOperator {
+			pos = opPos
+		}
+		ferr := p.errorAt(pos, msg)
+		ferr.Field = selector
+		ferr.Operator = op
+		return Group{}, ferr
+	}
+	return Group{Logic: And, Filters: []Filter{f}}, nil
+}
+
+func (p *rsqlParser) readOperator() (string, bool) {
+	rest := p.input[p.pos:]
+	for _, sym := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, sym) {
+			p.pos += len(sym)
+			return sym, true
+		}
+	}
+	if strings.HasPrefix(rest, "=") {
+		j := 1
+		for j < len(rest) && (rest[j] >= 'a' && rest[j] <= 'z' || rest[j] == '-') {
+			j++
+		}
+		if j > 1 && j < len(rest) && rest[j] == '=' {
+			p.pos += j + 1
+			return rest[:j+1], true
+		}
+	}
+	return "", false
+}
+
+func (p *rsqlParser) readArguments() ([]string, *FilterError) {
+	if p.peek() != '(' {
+		v, err := p.readValue()
+		if err != nil {
+			return nil, err
+		}
+		return []string{v}, nil
+	}
+
+	p.pos++
+	var args []string
+	for {
+		p.skipSpace()
+		v, err := p.readValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return args, nil
+		default:
+			return nil, p.errorAt(p.pos, "expected ',' or ')' in argument list")
+		}
+	}
+}
+
+func (p *rsqlParser) readValue() (string, *FilterError) {
+	if q := p.peek(); q == '"' || q == '\'' {
+		return p.readQuoted(false)
+	}
+	v := p.readWhile(func(c byte) bool { return !isRSQLReserved(c) })
+	if v == "" {
+		return "", p.errorAt(p.pos, "expected value")
+	}
+	return v, nil
+}
+
+func isRSQLReserved(c byte) bool {
+	return isSpace(c) || strings.IndexByte(`"'();,=!~<>`, c) >= 0
+}
+
+// rsqlFilter maps an RSQL comparison onto a Filter. On failure it returns a
+// message and whether the problem lies with the operator (vs. its arguments).
+func rsqlFilter(field, op string, args []string) (Filter, string, bool) {
+	var clause Clause
+	switch op {
+	case "==":
+		clause = Equals
+	case "!=":
+		clause = NotEquals
+	case "=gt=", ">":
+		clause = GreaterThan
+	case "=ge=", ">=":
+		clause = GreaterThanOrEq
+	case "=lt=", "<":
+		clause = LessThan
+	case "=le=", "<=":
+		clause = LessThanOrEq
+	case "=out=":
+		clause = NotIn
+	case "=like=":
+		clause = Contains
+	case "=notlike=":
+		clause = NotContains
+	case "=isnull=":
+		if len(args) != 1 {
+			return Filter{}, fmt.Sprintf("operator '%s' takes a single value", op), false
+		}
+		switch strings.ToLower(args[0]) {
+		case "true":
+			return Filter{Field: field, Operator: IsNull, Value: ""}, "", false
+		case "false":
+			return Filter{Field: field, Operator: IsNotNull, Value: ""}, "", false
+		default:
+			return Filter{}, fmt.Sprintf("operator '%s' expects true or false", op), false
+		}
+	default:
+		clause = Clause(strings.Trim(op, "="))
+		if !clause.IsValid() {
+			return Filter{}, fmt.Sprintf("unknown operator '%s'", op), true
+		}
+	}
+
+	switch clause {
+	case In, NotIn, Between, NotBetween:
+		// List values are passed on comma-separated, so a quoted ',' would
+		// split one value in two.
+		if slices.ContainsFunc(args, func(a string) bool { return strings.Contains(a, ",") }) {
+			return Filter{}, fmt.Sprintf("operator '%s' values cannot contain ','", op), false
+		}
+		return Filter{Field: field, Operator: clause, Value: strings.Join(args, ",")}, "", false
+	}
+	if len(args) != 1 {
+		return Filter{}, fmt.Sprintf("operator '%s' takes a single value", op), false
+	}
+
+	value := args[0]
+	switch clause {
+	case Equals, Contains:
+		if wc, inner := wildcardClause(value); wc != "" {
+			return Filter{Field: field, Operator: wc, Value: inner}, "", false
+		}
+	case NotEquals, NotContains:
+		if wc, inner := wildcardClause(value); wc == Contains {
+			return Filter{Field: field, Operator: NotContains, Value: inner}, "", false
+		}
+	}
+	return Filter{Field: field, Operator: clause, Value: value}, "", false
+}
+
+// wildcardClause interprets leading/trailing '*' as a LIKE pattern.
+func wildcardClause(value string) (Clause, string) {
+	lead := strings.HasPrefix(value, "*")
+	trail := len(value) > 1 && strings.HasSuffix(value, "*")
+	inner := strings.Trim(value, "*")
+	switch {
+	case lead && trail:
+		return Contains, inner
+	case lead:
+		return EndsWith, inner
+	case trail:
+		return StartsWith, inner
+	default:
+		return "", value
+	}
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRSQL_Precedence(t *testing.T) {
+	g, err := ParseRSQL("status==active;price=gt=10,name=like=*foo*")
+	require.Nil(t, err)
+
+	// AND binds tighter than OR: (status AND price) OR name
+	assert.Equal(t, Or, g.Logic)
+	require.Len(t, g.Filters, 1)
+	assert.Equal(t, Filter{Field: "name", Operator: Contains, Value: "foo"}, g.Filters[0])
+	require.Len(t, g.Groups, 1)
+	assert.Equal(t, And, g.Groups[0].Logic)
+	assert.Equal(t, []Filter{
+		{Field: "status", Operator: Equals, Value: "active"},
+		{Field: "price", Operator: GreaterThan, Value: "10"},
+	}, g.Groups[0].Filters)
+}
+
+func TestParseRSQL_Operators(t *testing.T) {
+	cases := map[string]Filter{
+		"age>=18":               {Field: "age", Operator: GreaterThanOrEq, Value: "18"},
+		"name==ali*":            {Field: "name", Operator: StartsWith, Value: "ali"},
+		"name!=*li*":            {Field: "name", Operator: NotContains, Value: "li"},
+		"name=in=(alice,'b o')": {Field: "name", Operator: In, Value: "alice,b o"},
+		"age=between=(1,5)":     {Field: "age", Operator: Between, Value: "1,5"},
+		"email=isnull=false":    {Field: "email", Operator: IsNotNull, Value: ""},
+		`name=="a\"b"`:          {Field: "name", Operator: Equals, Value: `a"b`},
+	}
+	for in, want := range cases {
+		g, err := ParseRSQL(in)
+		require.Nil(t, err, in)
+		assert.Equal(t, []Filter{want}, g.Filters, in)
+	}
+}
+
+func TestParseRSQL_KeywordsAndParens(t *testing.T) {
+	g, err := ParseRSQL("age=lt=18 and (name==bob or name==beta)")
+	require.Nil(t, err)
+	assert.Equal(t, And, g.Logic)
+	require.Len(t, g.Groups, 1)
+	assert.Equal(t, Or, g.Groups[0].Logic)
+	assert.Len(t, g.Groups[0].Filters, 2)
+}
+
+func TestParseRSQL_ErrorPosition(t *testing.T) {
+	cases := map[string]int{
+		"name=foo=bar":    5,
+		"name==a;":        9,
+		"(name==a":        9,
+		"name=='unclosed": 7,
+		"name==(a,b)":     7,
+	}
+	for in, col := range cases {
+		_, err := ParseRSQL(in)
+		require.NotNil(t, err, in)
+		assert.Equal(t, col, err.Position, in)
+		assert.Equal(t, CodeFilterParsing, err.Code, in)
+	}
+}
+
+func TestParseRSQL_ListValueWithComma(t *testing.T) {
+	for _, in := range []string{`name=in=("a,b",c)`, `name=out=('a,b')`, `age=between=("1,2",5)`} {
+		_, err := ParseRSQL(in)
+		require.NotNil(t, err, in)
+		assert.Equal(t, CodeFilterParsing, err.Code, in)
+		assert.Contains(t, err.Message, "cannot contain ','", in)
+	}
+}
+
+func TestApplier_ApplyGroup_Or(t *testing.T) {
+	db := mustDB(t)
+	v := NewValidator([]string{"name", "age"}, nil)
+	a := NewApplier(v)
+
+	g, perr := ParseRSQL("age=lt=18;name==bob,name==alice")
+	require.Nil(t, perr)
+
+	res, err := a.ApplyGroup(db.Model(&opUser{}), g, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, namesOf(fetch(t, res)))
+}
+
+func TestBuilder_WithRSQL(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter", "name==al*,age=gt=25")
+	q.Set("filter[age][gte]", "21")
+	c := newRequestWithQuery(q)
+
+	res := NewFromRequest(c, db.Model(&opUser{})).
+		AllowFields("name", "age").
+		WithRSQL("").
+		Apply().
+		Result()
+
+	// age >= 21 AND (name starts with "al" OR age > 25)
+	assert.Equal(t, []string{"ALF", "alina"}, namesOf(fetch(t, res)))
+}
+
+func TestBuilder_WithRSQL_FieldNotAllowed(t *testing.T) {
+	db := mustDB(t)
+
+	q := url.Values{}
+	q.Set("filter", "name==alice,email==a@x")
+	c := newRequestWithQuery(q)
+
+	b := NewFromRequest(c, db.Model(&opUser{})).
+		AllowFields("name").
+		WithRSQL("").
+		Apply()
+
+	require.False(t, b.OK())
+	assert.Equal(t, "email", b.GetErrors().First().Field)
+}
@@ -0,0 +1,89 @@
+package filter
+
+import "strings"
+
+// scanner is the byte cursor shared by the single-string expression parsers
+// (RSQL, AIP-160, OData).
+type scanner struct {
+	input string
+	pos   int
+}
+
+func (s *scanner) eof() bool { return s.pos >= len(s.input) }
+
+func (s *scanner) peek() byte {
+	if s.eof() {
+		return 0
+	}
+	return s.input[s.pos]
+}
+
+func (s *scanner) skipSpace() {
+	for !s.eof() && isSpace(s.input[s.pos]) {
+		s.pos++
+	}
+}
+
+// errorAt builds a syntax error for the 0-based byte offset pos.
+func (s *scanner) errorAt(pos int, message string) *FilterError {
+	return NewSyntaxError(s.input, pos+1, message)
+}
+
+// acceptKeyword consumes keyword when it appears at the cursor as a whole
+// word, i.e. followed by whitespace, '(' or the end of input.
+func (s *scanner) acceptKeyword(keyword string, foldCase bool) bool {
+	end := s.pos + len(keyword)
+	if end > len(s.input) {
+		return false
+	}
+	word := s.input[s.pos:end]
+	if word != keyword && (!foldCase || !strings.EqualFold(word, keyword)) {
+		return false
+	}
+	if end < len(s.input) && !isSpace(s.input[end]) && s.input[end] != '(' {
+		return false
+	}
+	s.pos = end
+	return true
+}
+
+// readQuoted reads a quoted string starting at the cursor. Backslash escapes
+// the next byte; doubleQuoteEscape additionally treats a doubled quote as an
+// escaped quote (OData style).
+func (s *scanner) readQuoted(doubleQuoteEscape bool) (string, *FilterError) {
+	quote := s.peek()
+	start := s.pos
+	s.pos++
+	var sb strings.Builder
+	for !s.eof() {
+		c := s.input[s.pos]
+		switch {
+		case c == quote && doubleQuoteEscape && s.pos+1 < len(s.input) && s.input[s.pos+1] == quote:
+			sb.WriteByte(quote)
+			s.pos += 2
+		case c == quote:
+			s.pos++
+			return sb.String(), nil
+		case c == '\\' && !doubleQuoteEscape && s.pos+1 < len(s.input):
+			sb.WriteByte(s.input[s.pos+1])
+			s.pos += 2
+		default:
+			sb.WriteByte(c)
+			s.pos++
+		}
+	}
+	return "", s.errorAt(start, "unterminated quoted value")
+}
+
+// readWhile consumes bytes for which keep returns true.
+func (s *scanner) readWhile(keep func(byte) bool) string {
+	start := s.pos
+	for !s.eof() && keep(s.input[s.pos]) {
+		s.pos++
+	}
+	return s.input[start:s.pos]
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,43 @@
+package filter
+
+import "slices"
+
+// WithScope adds server-side conditions, such as the tenant taken from the
+// authenticated user, that every query is restricted to:
+//
+//	filter.NewFromRequest(r, db.Model(&Invoice{})).
+//		AllowFields("status", "total").
+//		WithScope(filter.Filter{Field: "tenant_id", Operator: filter.Equals, Value: tenantID}).
+//		Apply()
+//
+// Scope filters are applied as their own AND group ahead of the client's
+// expression, so no client filter can widen them. They bypass the allowlist,
+// and any client filter on a scoped field is rejected as not allowed, even
+// when the field is also in the allowlist.
+func (b *Builder) WithScope(filters ...Filter) *Builder {
+	b.scope = append(b.scope, filters...)
+	return b
+}
+
+// isScoped reports whether field is restricted by WithScope.
+func (b *Builder) isScoped(field string) bool {
+	return slices.ContainsFunc(b.scope, func(f Filter) bool { return f.Field == field })
+}
+
+// checkScope rejects client filters on scoped fields.
+func (b *Builder) checkScope(expr Group) {
+	if len(b.scope) == 0 {
+		return
+	}
+	var allowed []string
+	for _, field := range b.validator.GetAllowedFields() {
+		if !b.isScoped(field) {
+			allowed = append(allowed, field)
+		}
+	}
+	for _, f := range expr.AllFilters() {
+		if b.isScoped(f.Field) {
+			b.result.AddError(NewFieldNotAllowedError(f.Field, allowed).at(f.Parameter))
+		}
+	}
+}
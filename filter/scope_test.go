@@ -0,0 +1,44 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_WithScope(t *testing.T) {
+	db := mustDB(t)
+
+	// the client OR group cannot escape the scope
+	b := NewFromValues(url.Values{"filter": {"name==bob,name==alice"}}, db.Model(&opUser{})).
+		AllowFields("name", "age").
+		WithRSQL("").
+		WithScope(Filter{Field: "age", Operator: LessThan, Value: 18}).
+		Apply()
+	require.True(t, b.OK(), "unexpected errors: %+v", b.GetErrors())
+
+	var got []opUser
+	require.NoError(t, b.Query().Find(&got).Error)
+	require.Len(t, got, 1)
+	assert.Equal(t, "bob", got[0].Name)
+
+	total, _, err := b.Count()
+	require.Nil(t, err)
+	assert.EqualValues(t, 1, total)
+}
+
+func TestBuilder_WithScope_ClientOverride(t *testing.T) {
+	db := mustDB(t)
+
+	b := NewFromValues(url.Values{"filter[age][gte]": {"0"}}, db.Model(&opUser{})).
+		AllowFields("name", "age").
+		WithScope(Filter{Field: "age", Operator: LessThan, Value: 18}).
+		Apply()
+
+	require.False(t, b.OK())
+	first := b.GetErrors().First()
+	assert.Equal(t, "age", first.Field)
+	assert.NotContains(t, first.Suggestions, "age")
+}
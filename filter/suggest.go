@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions caps the "did you mean" suggestions of an error.
+const maxSuggestions = 3
+
+// suggest returns the candidates most similar to token, best first: those
+// sharing a prefix with it, then abbreviations (token's letters in order,
+// "ge" for "gte"), then the rest within a small edit distance. Unlike the
+// full allowlist this stays short and reveals little of the schema.
+// See Builder.ExposeAllowlist for the old behaviour.
+func suggest(token string, candidates []string) []string {
+	t := strings.ToLower(token)
+	maxDist := max(1, len(t)/3)
+
+	type scored struct {
+		name string
+		rank int // 0 prefix, 1 abbreviation, 2 edit distance
+		dist int
+	}
+	var matches []scored
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		m := scored{name: c, rank: 2, dist: editDistance(t, lc)}
+		switch {
+		case t != "" && (strings.HasPrefix(lc, t) || strings.HasPrefix(t, lc)):
+			m.rank = 0
+		case m.dist > maxDist:
+			continue
+		case isSubsequence(t, lc) || isSubsequence(lc, t):
+			m.rank = 1
+		}
+		matches = append(matches, m)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].dist < matches[j].dist
+	})
+
+	out := make([]string, 0, min(len(matches), maxSuggestions))
+	for _, m := range matches {
+		if len(out) == maxSuggestions {
+			break
+		}
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// isSubsequence reports whether the bytes of a appear in b in order.
+func isSubsequence(a, b string) bool {
+	i := 0
+	for j := 0; i < len(a) && j < len(b); j++ {
+		if a[i] == b[j] {
+			i++
+		}
+	}
+	return i == len(a)
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: Levenshtein distance where swapping two adjacent letters ("nmae" for
+// "name") is a single edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// ExposeAllowlist makes "not allowed" errors suggest the complete allowlist
+// instead of the few closest matches. Only use it where the schema is not
+// sensitive, e.g. internal APIs.
+func (b *Builder) ExposeAllowlist() *Builder {
+	b.exposeAllowlist = true
+	return b
+}
+
+// finishErrors prepares errors for the client: it swaps ranked suggestions
+// for the full allowlist when ExposeAllowlist is set and localizes messages
+// when a Translator is configured.
+func (b *Builder) finishErrors(errs []*FilterError) {
+	if b.exposeAllowlist {
+		for _, e := range errs {
+			if e.allowed != nil {
+				e.Suggestions = append([]string(nil), e.allowed...)
+			}
+		}
+	}
+	b.translate(errs)
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T) {
+	fields := []string{"id", "name", "email", "created_at", "updated_at", "status", "price", "category"}
+
+	assert.Equal(t, []string{"created_at"}, suggest("creatd_at", fields))
+	assert.Equal(t, []string{"status"}, suggest("stat", fields))
+	assert.Empty(t, suggest("password", fields))
+	assert.Equal(t, []string{"gte", "gt"}, suggest("ge", []string{"eq", "gte", "gt", "between"})[:2])
+	assert.LessOrEqual(t, len(suggest("a", fields)), maxSuggestions)
+
+	ops := make([]string, len(allClauses))
+	for i, c := range allClauses {
+		ops[i] = string(c)
+	}
+	assert.Equal(t, "gte", suggest("ge", ops)[0])
+	assert.Equal(t, []string{"gt", "gte"}, suggest("g", ops))
+	assert.Empty(t, suggest("xy", ops))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 1, editDistance("ge", "gte"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 4, editDistance("", "name"))
+	assert.Equal(t, 1, editDistance("nmae", "name"))
+}
+
+func TestBuilder_Suggestions(t *testing.T) {
+	db := mustDB(t)
+	q := url.Values{"filter[nmae]": {"bob"}}
+
+	b := NewFromValues(q, db.Model(&opUser{})).AllowFields("name", "age", "email").Apply()
+	require.False(t, b.OK())
+	assert.Equal(t, []string{"name"}, b.GetErrors().First().Suggestions)
+
+	b = NewFromValues(q, db.Model(&opUser{})).AllowFields("name", "age", "email").ExposeAllowlist().Apply()
+	require.False(t, b.OK())
+	assert.Equal(t, []string{"name", "age", "email"}, b.GetErrors().First().Suggestions)
+}
@@ -0,0 +1,81 @@
+// Code generated by golens-ts. DO NOT EDIT.
+
+/** A non-empty list, sent comma-separated. */
+export type List<T> = [T, ...T[]];
+
+type RawQuery = {
+  filter?: Record<string, unknown>;
+  sort?: string[];
+  page?: { number?: number; size?: number };
+  include?: string[];
+  fields?: string[];
+};
+
+function encodeValue(value: unknown): string {
+  if (Array.isArray(value)) return value.map(encodeValue).join(",");
+  if (value instanceof Date) return value.toISOString();
+  return String(value);
+}
+
+function buildQuery(query: RawQuery, fieldsKey: string): string {
+  const params = new URLSearchParams();
+  for (const [field, condition] of Object.entries(query.filter ?? {})) {
+    if (condition === undefined) continue;
+    if (typeof condition !== "object" || condition === null || condition instanceof Date) {
+      params.set("filter[" + field + "]", encodeValue(condition));
+      continue;
+    }
+    for (const [op, value] of Object.entries(condition)) {
+      if (value === undefined) continue;
+      params.set("filter[" + field + "][" + op + "]", op === "null" || op === "not-null" ? "" : encodeValue(value));
+    }
+  }
+  if (query.sort?.length) params.set("sort", query.sort.join(","));
+  if (query.page?.number !== undefined) params.set("page[number]", String(query.page.number));
+  if (query.page?.size !== undefined) params.set("page[size]", String(query.page.size));
+  if (query.include?.length) params.set("include", query.include.join(","));
+  if (query.fields?.length) params.set(fieldsKey, query.fields.join(","));
+  return params.toString();
+}
+
+// order-items
+
+export type OrderItemsFilter = {
+  total?: { ne?: number };
+};
+
+export type OrderItemsSort = never;
+
+export type OrderItemsQuery = {
+  filter?: OrderItemsFilter;
+};
+
+/** Builds the query string of the order-items endpoint. */
+export function orderItemsQuery(query: OrderItemsQuery): string {
+  return buildQuery(query, "fields");
+}
+
+// users
+
+export type UsersFilter = {
+  /** Filter by age */
+  age?: { gt?: number; between?: [number, number]; null?: true };
+  /** Filter by name */
+  name: "alice" | "bob" | { eq?: "alice" | "bob"; in?: List<"alice" | "bob">; "starts-with"?: string };
+  /** Filter by comments.approved */
+  "comments.approved"?: boolean | { eq?: boolean };
+};
+
+export type UsersSort = "name" | "-name";
+
+export type UsersQuery = {
+  filter: UsersFilter;
+  sort?: UsersSort[];
+  page?: { number?: number; size?: number };
+  fields?: ("id" | "name")[];
+};
+
+/** Builds the query string of the users endpoint. */
+export function usersQuery(query: UsersQuery): string {
+  return buildQuery(query, "fields[users]");
+}
@@ -0,0 +1,28 @@
+import { orderItemsQuery, usersQuery } from "./filters";
+
+usersQuery({ filter: { name: "alice" } });
+usersQuery({
+  filter: { name: { in: ["alice", "bob"] }, age: { between: [18, 30], null: true }, "comments.approved": false },
+  sort: ["-name"],
+  page: { number: 2, size: 20 },
+  fields: ["id"],
+});
+orderItemsQuery({});
+orderItemsQuery({ filter: { total: { ne: 9.99 } } });
+
+// @ts-expect-error name is required
+usersQuery({ filter: {} });
+// @ts-expect-error gt is not allowed on name
+usersQuery({ filter: { name: { gt: "a" } } });
+// @ts-expect-error name only takes its enum values
+usersQuery({ filter: { name: "carol" } });
+// @ts-expect-error in takes a non-empty list
+usersQuery({ filter: { name: { in: [] } } });
+// @ts-expect-error age has no bare (eq) form
+usersQuery({ filter: { name: "bob", age: 18 } });
+// @ts-expect-error between takes a pair
+usersQuery({ filter: { name: "bob", age: { between: [18] } } });
+// @ts-expect-error unknown sort field
+usersQuery({ filter: { name: "bob" }, sort: ["age"] });
+// @ts-expect-error order-items has no sorts
+orderItemsQuery({ sort: ["total"] });
@@ -0,0 +1,266 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GenerateTypeScript writes a TypeScript module for the named Capabilities
+// (see Builder.Capabilities): per schema, a filter type listing exactly the
+// operators each field allows with their value types, a query type with
+// sort, paging, include and fieldset parameters, and a function building the
+// query string Parser.Parse accepts:
+//
+//	// schemas: {"users": users(filter.NewFromValues(nil, db.Model(&User{}))).Capabilities()}
+//	usersQuery({filter: {age: {gt: 18}, status: {in: ["new", "paid"]}}, sort: ["-name"]})
+//	// "filter%5Bage%5D%5Bgt%5D=18&filter%5Bstatus%5D%5Bin%5D=new%2Cpaid&sort=-name"
+//
+// A field that allows eq also takes a bare value (filter[field]=value).
+// in and not-in take non-empty arrays, between and not-between a pair, and
+// null and not-null true. Schemas are emitted in name order; names become
+// identifiers ("order-items" gives OrderItemsFilter and orderItemsQuery).
+// Names that give an identifier already in use, fields listed twice and
+// Unrestricted schemas are errors, as the module would not type-check.
+func GenerateTypeScript(w io.Writer, schemas map[string]*Capabilities) error {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Distinct names can map to one identifier ("user-orders" and
+	// "user_orders"), which would declare it twice.
+	idents := map[string]string{"Raw": "the prelude's RawQuery", "Build": "the prelude's buildQuery"}
+	var buf bytes.Buffer
+	buf.WriteString(tsPrelude)
+	for _, name := range names {
+		typ := tsTypeName(name)
+		if typ == "" {
+			return fmt.Errorf("typescript: schema name %q has no identifier characters", name)
+		}
+		if other, dup := idents[typ]; dup {
+			return fmt.Errorf("typescript: schema name %q gives the same identifiers as %s", name, other)
+		}
+		idents[typ] = fmt.Sprintf("schema %q", name)
+		if schemas[name].Unrestricted {
+			return fmt.Errorf("typescript: schema %q has no filter allowlist to type", name)
+		}
+		keys := make(map[string]bool, len(schemas[name].Fields))
+		for _, f := range schemas[name].Fields {
+			if keys[tsKey(f.Name)] {
+				return fmt.Errorf("typescript: schema %q lists field %q twice", name, f.Name)
+			}
+			keys[tsKey(f.Name)] = true
+		}
+		writeTSSchema(&buf, name, schemas[name])
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// tsPrelude is the runtime shared by every generated query builder.
+const tsPrelude = `// Code generated by golens-ts. DO NOT EDIT.
+
+/** A non-empty list, sent comma-separated. */
+export type List<T> = [T, ...T[]];
+
+type RawQuery = {
+  filter?: Record<string, unknown>;
+  sort?: string[];
+  page?: { number?: number; size?: number };
+  include?: string[];
+  fields?: string[];
+};
+
+function encodeValue(value: unknown): string {
+  if (Array.isArray(value)) return value.map(encodeValue).join(",");
+  if (value instanceof Date) return value.toISOString();
+  return String(value);
+}
+
+function buildQuery(query: RawQuery, fieldsKey: string): string {
+  const params = new URLSearchParams();
+  for (const [field, condition] of Object.entries(query.filter ?? {})) {
+    if (condition === undefined) continue;
+    if (typeof condition !== "object" || condition === null || condition instanceof Date) {
+      params.set("filter[" + field + "]", encodeValue(condition));
+      continue;
+    }
+    for (const [op, value] of Object.entries(condition)) {
+      if (value === undefined) continue;
+      params.set("filter[" + field + "][" + op + "]", op === "null" || op === "not-null" ? "" : encodeValue(value));
+    }
+  }
+  if (query.sort?.length) params.set("sort", query.sort.join(","));
+  if (query.page?.number !== undefined) params.set("page[number]", String(query.page.number));
+  if (query.page?.size !== undefined) params.set("page[size]", String(query.page.size));
+  if (query.include?.length) params.set("include", query.include.join(","));
+  if (query.fields?.length) params.set(fieldsKey, query.fields.join(","));
+  return params.toString();
+}
+`
+
+// writeTSSchema writes the types and query builder of one schema.
+func writeTSSchema(buf *bytes.Buffer, name string, caps *Capabilities) {
+	typ := tsTypeName(name)
+	first, size := utf8.DecodeRuneInString(typ)
+	fn := string(unicode.ToLower(first)) + typ[size:] + "Query"
+
+	fmt.Fprintf(buf, "\n// %s\n\n", name)
+
+	filterRequired := false
+	// Type aliases rather than interfaces: only object literal types are
+	// assignable to buildQuery's Record<string, unknown> under --strict.
+	fmt.Fprintf(buf, "export type %sFilter = {\n", typ)
+	for _, f := range caps.Fields {
+		if f.Description != "" {
+			fmt.Fprintf(buf, "  /** %s */\n", strings.ReplaceAll(f.Description, "*/", "*\\/"))
+		}
+		optional := "?"
+		if f.Required {
+			optional, filterRequired = "", true
+		}
+		fmt.Fprintf(buf, "  %s%s: %s;\n", tsKey(f.Name), optional, tsCondition(f))
+	}
+	buf.WriteString("};\n\n")
+
+	fmt.Fprintf(buf, "export type %sSort = %s;\n\n", typ, tsUnion(caps.Sorts))
+
+	fmt.Fprintf(buf, "export type %sQuery = {\n", typ)
+	if filterRequired {
+		fmt.Fprintf(buf, "  filter: %sFilter;\n", typ)
+	} else {
+		fmt.Fprintf(buf, "  filter?: %sFilter;\n", typ)
+	}
+	if len(caps.Sorts) > 0 {
+		fmt.Fprintf(buf, "  sort?: %sSort[];\n", typ)
+	}
+	if caps.Pagination != nil {
+		buf.WriteString("  page?: { number?: number; size?: number };\n")
+	}
+	if len(caps.Includes) > 0 {
+		fmt.Fprintf(buf, "  include?: (%s)[];\n", tsUnion(caps.Includes))
+	}
+	if len(caps.Select) > 0 {
+		fmt.Fprintf(buf, "  fields?: (%s)[];\n", tsUnion(caps.Select))
+	}
+	buf.WriteString("};\n\n")
+
+	fieldsKey := "fields"
+	if caps.Resource != "" {
+		fieldsKey = "fields[" + caps.Resource + "]"
+	}
+	fmt.Fprintf(buf, "/** Builds the query string of the %s endpoint. */\n", name)
+	fmt.Fprintf(buf, "export function %s(query: %sQuery): string {\n", fn, typ)
+	fmt.Fprintf(buf, "  return buildQuery(query, %s);\n}\n", tsString(fieldsKey))
+}
+
+// tsCondition is the type of a field's entry in a filter: an operator
+// object, or also a bare value when eq is allowed.
+func tsCondition(f FieldCapability) string {
+	value := tsValueType(f.Type, nil)
+	enumValue := tsValueType(f.Type, f.Enum)
+
+	ops := make([]string, 0, len(f.Operators))
+	for _, op := range f.Operators {
+		var t string
+		switch op {
+		case Equals, NotEquals:
+			t = enumValue
+		case Contains, NotContains, StartsWith, EndsWith:
+			t = "string"
+		case In, NotIn:
+			t = "List<" + enumValue + ">"
+		case Between, NotBetween:
+			t = "[" + value + ", " + value + "]"
+		case IsNull, IsNotNull:
+			t = "true"
+		default:
+			t = value
+		}
+		ops = append(ops, fmt.Sprintf("%s?: %s", tsKey(string(op)), t))
+	}
+
+	object := "{ " + strings.Join(ops, "; ") + " }"
+	if slices.Contains(f.Operators, Equals) {
+		return enumValue + " | " + object
+	}
+	return object
+}
+
+// tsValueType is the TypeScript type of a single value of t. Enum values
+// become string literals, as they appear in the query string.
+func tsValueType(t ValueType, enum []string) string {
+	if len(enum) > 0 {
+		return tsUnion(enum)
+	}
+	switch t {
+	case TypeInteger, TypeNumber:
+		return "number"
+	case TypeBoolean:
+		return "boolean"
+	case TypeDateTime:
+		return "string | Date"
+	default:
+		return "string"
+	}
+}
+
+// tsUnion is a union of string literals, or never when values is empty.
+func tsUnion(values []string) string {
+	if len(values) == 0 {
+		return "never"
+	}
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = tsString(v)
+	}
+	return strings.Join(literals, " | ")
+}
+
+// tsKey is name as a property key, quoted unless it is an identifier.
+func tsKey(name string) string {
+	for i, r := range name {
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return tsString(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// tsString is s as a string literal.
+func tsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// tsTypeName turns a schema name into a PascalCase identifier, e.g.
+// "order-items" into "OrderItems".
+func tsTypeName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if sb.Len() == 0 && unicode.IsDigit(r) {
+			sb.WriteByte('_')
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func generatedTypeScript(t *testing.T) []byte {
+	t.Helper()
+	db := mustDB(t)
+
+	users := NewFromValues(nil, db.Model(&opUser{})).
+		AllowConfigs(
+			AllowedFilter("age", GreaterThan, Between, IsNull),
+			AllowedFilter("name", Equals, In, StartsWith).WithEnum("alice", "bob").AsRequired(),
+			AllowedFilter("comments.approved", Equals).WithType(TypeBoolean),
+		).
+		AllowSorts("name").
+		Paginate(20, 100).
+		AllowSelect("id", "name").
+		WithResource("users").
+		Capabilities()
+	orders := &Capabilities{Fields: []FieldCapability{{Name: "total", Type: TypeNumber, Operators: []Clause{NotEquals}}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, GenerateTypeScript(&buf, map[string]*Capabilities{"users": users, "order-items": orders}))
+	return buf.Bytes()
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	got := generatedTypeScript(t)
+
+	golden := filepath.Join("testdata", "typescript.golden.ts")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "run go test -update to regenerate %s", golden)
+
+	assert.Error(t, GenerateTypeScript(&bytes.Buffer{}, map[string]*Capabilities{"--": {}}))
+	assert.Error(t, GenerateTypeScript(&bytes.Buffer{}, map[string]*Capabilities{"users": {Unrestricted: true}}))
+}
+
+func TestGenerateTypeScript_Collisions(t *testing.T) {
+	field := func(names ...string) *Capabilities {
+		caps := &Capabilities{}
+		for _, n := range names {
+			caps.Fields = append(caps.Fields, FieldCapability{Name: n, Type: TypeString, Operators: []Clause{Equals}})
+		}
+		return caps
+	}
+
+	for name, schemas := range map[string]map[string]*Capabilities{
+		"schema names": {"user_orders": field("a"), "user-orders": field("a")},
+		"prelude type": {"raw": field("a")},
+		"prelude func": {"build": field("a")},
+		"field names":  {"users": field("a.b", "a_b", "a.b")},
+	} {
+		assert.Error(t, GenerateTypeScript(&bytes.Buffer{}, schemas), name)
+	}
+
+	// "a.b" is a quoted key, so it does not clash with a_b.
+	var buf bytes.Buffer
+	require.NoError(t, GenerateTypeScript(&buf, map[string]*Capabilities{"users": field("a.b", "a_b")}))
+	assert.Contains(t, buf.String(), `  "a.b"?: string | { eq?: string };`)
+	assert.Contains(t, buf.String(), `  a_b?: string | { eq?: string };`)
+}
+
+// TestGenerateTypeScript_Compiles type-checks the generated module and
+// typescript_usage.ts, which also asserts that invalid filters are rejected,
+// with tsc --strict. It is skipped when tsc is not installed.
+func TestGenerateTypeScript_Compiles(t *testing.T) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc not found in PATH")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "filters.ts"), generatedTypeScript(t), 0o644))
+	usage, err := os.ReadFile(filepath.Join("testdata", "typescript_usage.ts"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "usage.ts"), usage, 0o644))
+
+	cmd := exec.Command(tsc, "--strict", "--noEmit", "--target", "es2020", "--lib", "es2020,dom",
+		filepath.Join(dir, "filters.ts"), filepath.Join(dir, "usage.ts"))
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "tsc: %s", out)
+}
+
+func TestTSTypeName(t *testing.T) {
+	assert.Equal(t, "Users", tsTypeName("users"))
+	assert.Equal(t, "OrderItems", tsTypeName("order-items"))
+	assert.Equal(t, "V2Users", tsTypeName("v2/users"))
+	assert.Equal(t, "_2fa", tsTypeName("2fa"))
+}
@@ -87,7 +87,7 @@ func (v *Validator) ValidateFilter(f Filter) *FilterError {
 
 	// Field must be non-empty
 	if strings.TrimSpace(f.Field) == "" {
-		return NewValidationError("", string(f.Operator), fmt.Sprintf("%v", f.Value), "Field name cannot be empty")
+		return NewEmptyFieldError(string(f.Operator), fmt.Sprintf("%v", f.Value))
 	}
 
 	// Field allow-check